@@ -77,6 +77,7 @@ func (a adminAPIHandlers) DelConfigKVHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	prevCfg := cfg.Clone()
 	if err = cfg.DelFrom(bytes.NewReader(kvBytes)); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
@@ -91,13 +92,16 @@ func (a adminAPIHandlers) DelConfigKVHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	dynamic := config.SubSystemsDynamic.Contains(subSys)
+	dynamic := config.IsDynamic(subSys)
 	if dynamic {
-		applyDynamic(ctx, objectAPI, cfg, subSys, r, w)
+		applyDynamic(ctx, objectAPI, cfg, subSys, prevCfg.RestartRequiredAfter(cfg), r, w)
 	}
 }
 
-func applyDynamic(ctx context.Context, objectAPI ObjectLayer, cfg config.Config, subSys string,
+// applyDynamic - applies the dynamic values of subSys, the client is told
+// that the config was applied unless restart is set, i.e. a changed key
+// only takes effect after a restart.
+func applyDynamic(ctx context.Context, objectAPI ObjectLayer, cfg config.Config, subSys string, restart bool,
 	r *http.Request, w http.ResponseWriter,
 ) {
 	// Apply dynamic values.
@@ -106,6 +110,9 @@ func applyDynamic(ctx context.Context, objectAPI ObjectLayer, cfg config.Config,
 		return
 	}
 	globalNotificationSys.SignalConfigReload(subSys)
+	if restart {
+		return
+	}
 	// Tell the client that dynamic config was applied.
 	w.Header().Set(madmin.ConfigAppliedHeader, madmin.ConfigAppliedTrue)
 }
@@ -141,6 +148,7 @@ func (a adminAPIHandlers) SetConfigKVHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	prevCfg := cfg.Clone()
 	dynamic, err := cfg.ReadConfig(bytes.NewReader(kvBytes))
 	if err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
@@ -158,6 +166,13 @@ func (a adminAPIHandlers) SetConfigKVHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if globalConfigProbeEndpoints {
+		if err = probeConfigEndpoints(ctx, cfg, subSys, globalConfigProbeEndpointsStrict); err != nil {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), err.Error(), r.URL)
+			return
+		}
+	}
+
 	// Update the actual server config on disk.
 	if err = saveServerConfig(ctx, objectAPI, cfg); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
@@ -171,7 +186,7 @@ func (a adminAPIHandlers) SetConfigKVHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	if dynamic {
-		applyDynamic(ctx, objectAPI, cfg, subSys, r, w)
+		applyDynamic(ctx, objectAPI, cfg, subSys, prevCfg.RestartRequiredAfter(cfg), r, w)
 	}
 	writeSuccessResponseHeadersOnly(w)
 }
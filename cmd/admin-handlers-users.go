@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"io"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/minio/madmin-go"
+	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/minio/internal/auth"
 	"github.com/minio/minio/internal/config/dns"
 	"github.com/minio/minio/internal/logger"
@@ -1512,3 +1514,28 @@ func (a adminAPIHandlers) SetPolicyForUserOrGroup(w http.ResponseWriter, r *http
 		return
 	}
 }
+
+// detectCredentialReuse - returns the sorted access keys of creds that
+// share their secret key with another access key. Secrets are only
+// compared by their hash and never returned or logged.
+func detectCredentialReuse(creds []auth.Credentials) []string {
+	accessKeys := make(map[[sha256.Size]byte]set.StringSet)
+	for _, cred := range creds {
+		if cred.AccessKey == "" || cred.SecretKey == "" {
+			continue
+		}
+		sum := sha256.Sum256([]byte(cred.SecretKey))
+		if _, ok := accessKeys[sum]; !ok {
+			accessKeys[sum] = set.NewStringSet()
+		}
+		accessKeys[sum].Add(cred.AccessKey)
+	}
+
+	reused := set.NewStringSet()
+	for _, keys := range accessKeys {
+		if len(keys) > 1 {
+			reused = reused.Union(keys)
+		}
+	}
+	return reused.ToSlice()
+}
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -1363,3 +1364,20 @@ func mustGenerateCredentials(c *check) (string, string) {
 	}
 	return ak, sk
 }
+
+func TestDetectCredentialReuse(t *testing.T) {
+	creds := []auth.Credentials{
+		{AccessKey: "minioadmin", SecretKey: "shared-secret-key"},
+		{AccessKey: "svcacct1", SecretKey: "unique-secret-key"},
+		{AccessKey: "svcacct2", SecretKey: "shared-secret-key"},
+		// The same access key listed twice is not a reuse.
+		{AccessKey: "svcacct1", SecretKey: "unique-secret-key"},
+	}
+	reused := detectCredentialReuse(creds)
+	if expected := []string{"minioadmin", "svcacct2"}; !reflect.DeepEqual(reused, expected) {
+		t.Fatalf("Expected %v, got %v", expected, reused)
+	}
+	if reused = detectCredentialReuse(creds[1:2]); len(reused) != 0 {
+		t.Fatalf("Expected no reuse, got %v", reused)
+	}
+}
@@ -563,14 +563,12 @@ func (a adminAPIHandlers) StartProfilingHandler(w http.ResponseWriter, r *http.R
 		hostErrs = append(hostErrs, globalNotificationSys.StartProfiling(profiler)...)
 
 		// Start profiling locally as well.
-		prof, err := startProfiler(profiler)
-		if err != nil {
+		if err := startAndInstallProfiler(profiler); err != nil {
 			hostErrs = append(hostErrs, NotificationPeerErr{
 				Host: *thisAddr,
 				Err:  err,
 			})
 		} else {
-			globalProfiler[profiler] = prof
 			hostErrs = append(hostErrs, NotificationPeerErr{
 				Host: *thisAddr,
 			})
@@ -601,7 +599,8 @@ func (a adminAPIHandlers) StartProfilingHandler(w http.ResponseWriter, r *http.R
 
 // ProfileHandler - POST /minio/admin/v3/profile/?profilerType={profilerType}
 // ----------
-// Enable server profiling
+// Enable server profiling, with folded=true the CPU and heap profiles
+// are also returned as folded stacks for flamegraph tools.
 func (a adminAPIHandlers) ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "Profile")
 
@@ -629,6 +628,7 @@ func (a adminAPIHandlers) ProfileHandler(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
+	folded := r.Form.Get("folded") == "true"
 	// read request body
 	io.CopyN(ioutil.Discard, r.Body, 1)
 
@@ -649,10 +649,7 @@ func (a adminAPIHandlers) ProfileHandler(w http.ResponseWriter, r *http.Request)
 		globalNotificationSys.StartProfiling(profiler)
 
 		// Start profiling locally as well.
-		prof, err := startProfiler(profiler)
-		if err == nil {
-			globalProfiler[profiler] = prof
-		}
+		startAndInstallProfiler(profiler)
 	}
 	globalProfilerMu.Unlock()
 
@@ -667,7 +664,7 @@ func (a adminAPIHandlers) ProfileHandler(w http.ResponseWriter, r *http.Request)
 			}
 			return
 		case <-timer.C:
-			if !globalNotificationSys.DownloadProfilingData(ctx, w) {
+			if !globalNotificationSys.DownloadProfilingData(ctx, w, folded) {
 				writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminProfilerNotEnabled), r.URL)
 				return
 			}
@@ -714,7 +711,7 @@ func (a adminAPIHandlers) DownloadProfilingHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	if !globalNotificationSys.DownloadProfilingData(ctx, w) {
+	if !globalNotificationSys.DownloadProfilingData(ctx, w, false) {
 		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminProfilerNotEnabled), r.URL)
 		return
 	}
@@ -1777,7 +1774,7 @@ func getKubernetesInfo(dctx context.Context) madmin.KubernetesInfo {
 	}
 
 	client := &http.Client{
-		Transport: NewGatewayHTTPTransport(),
+		Transport: globalTransports.Get(transportRoleGateway),
 		Timeout:   10 * time.Second,
 	}
 
@@ -2544,7 +2541,7 @@ func checkConnection(endpointStr string, timeout time.Duration) error {
 	defer cancel()
 
 	client := &http.Client{
-		Transport: globalProxyTransport,
+		Transport: globalTransports.Get(transportRoleProxy),
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpointStr, nil)
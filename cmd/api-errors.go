@@ -1894,8 +1894,18 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrInvalidRange
 	case errDataTooLarge:
 		apiErr = ErrEntityTooLarge
+	case errRequestBodyTooLarge:
+		apiErr = ErrEntityTooLarge
 	case errDataTooSmall:
 		apiErr = ErrEntityTooSmall
+	case errTooManyParts:
+		apiErr = ErrInvalidMaxParts
+	case errInvalidPartID:
+		apiErr = ErrInvalidMaxParts
+	case errPresignNotYetValid:
+		apiErr = ErrRequestNotReadyYet
+	case errPresignExpired:
+		apiErr = ErrExpiredPresignRequest
 	case errAuthentication:
 		apiErr = ErrAccessDenied
 	case auth.ErrInvalidAccessKeyLength:
@@ -1936,6 +1946,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrOperationTimedOut
 	case errDiskNotFound:
 		apiErr = ErrSlowDown
+	case errBackendSlowDown:
+		apiErr = ErrSlowDown
 	case objectlock.ErrInvalidRetentionDate:
 		apiErr = ErrInvalidRetentionDate
 	case objectlock.ErrPastObjectLockRetainDate:
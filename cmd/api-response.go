@@ -759,6 +759,70 @@ const (
 	mimeXML mimeType = "application/xml"
 )
 
+// negotiateResponseFormat returns the media type of supported which is
+// preferred by the Accept header of r, as weighted by q-values, ties are
+// resolved in the order of supported. An exact media type takes precedence
+// over a "type/*" range, which takes precedence over "*/*". Returns XML,
+// the S3 convention, when no Accept header is sent or none of supported is
+// acceptable.
+func negotiateResponseFormat(r *http.Request, supported ...string) string {
+	accept := r.Header.Values(xhttp.Accept)
+	if len(accept) == 0 || len(supported) == 0 {
+		return string(mimeXML)
+	}
+
+	type mediaRange struct {
+		typ         string
+		q           float64
+		specificity int
+	}
+	var ranges []mediaRange
+	for _, value := range accept {
+		for _, part := range strings.Split(value, ",") {
+			params := strings.Split(part, ";")
+			typ := strings.ToLower(strings.TrimSpace(params[0]))
+			if typ == "" {
+				continue
+			}
+			mr := mediaRange{typ: typ, q: 1, specificity: 2}
+			switch {
+			case typ == "*/*":
+				mr.specificity = 0
+			case strings.HasSuffix(typ, "/*"):
+				mr.specificity = 1
+			}
+			for _, param := range params[1:] {
+				kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(kv) == 2 && strings.EqualFold(kv[0], "q") {
+					q, err := strconv.ParseFloat(kv[1], 64)
+					if err != nil || q < 0 || q > 1 {
+						q = 0
+					}
+					mr.q = q
+				}
+			}
+			ranges = append(ranges, mr)
+		}
+	}
+
+	best, bestQ := string(mimeXML), 0.0
+	for _, s := range supported {
+		typ := strings.ToLower(s)
+		q, specificity := 0.0, -1
+		for _, mr := range ranges {
+			matches := mr.typ == typ || mr.typ == "*/*" ||
+				mr.specificity == 1 && strings.HasPrefix(typ, strings.TrimSuffix(mr.typ, "*"))
+			if matches && mr.specificity > specificity {
+				q, specificity = mr.q, mr.specificity
+			}
+		}
+		if q > bestQ {
+			best, bestQ = s, q
+		}
+	}
+	return best
+}
+
 // writeSuccessResponseJSON writes success headers and response if any,
 // with content-type set to `application/json`.
 func writeSuccessResponseJSON(w http.ResponseWriter, response []byte) {
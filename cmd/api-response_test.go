@@ -123,3 +123,38 @@ func TestGetURLScheme(t *testing.T) {
 		t.Errorf("Expected %s, got %s", httpsScheme, gotScheme)
 	}
 }
+
+// Tests content negotiation of the response format.
+func TestNegotiateResponseFormat(t *testing.T) {
+	supported := []string{string(mimeXML), string(mimeJSON)}
+	testCases := []struct {
+		accept   []string
+		expected mimeType
+	}{
+		{accept: nil, expected: mimeXML},
+		{accept: []string{"application/json"}, expected: mimeJSON},
+		{accept: []string{"Application/JSON; charset=utf-8"}, expected: mimeJSON},
+		{accept: []string{"application/xml;q=0.5, application/json;q=0.9"}, expected: mimeJSON},
+		{accept: []string{"application/json;q=0.2", "application/xml;q=0.8"}, expected: mimeXML},
+		{accept: []string{"*/*"}, expected: mimeXML},
+		{accept: []string{"application/*;q=0.5, application/json;q=0"}, expected: mimeXML},
+		{accept: []string{"*/*;q=0.1, application/json"}, expected: mimeJSON},
+		{accept: []string{"text/html"}, expected: mimeXML},
+		{accept: []string{"application/json;q=invalid"}, expected: mimeXML},
+	}
+	for i, testCase := range testCases {
+		r := &http.Request{Header: http.Header{}}
+		for _, accept := range testCase.accept {
+			r.Header.Add("Accept", accept)
+		}
+		if got := negotiateResponseFormat(r, supported...); got != string(testCase.expected) {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+
+	// JSON only handlers still fall back to XML.
+	r := &http.Request{Header: http.Header{"Accept": {"text/plain"}}}
+	if got := negotiateResponseFormat(r, string(mimeJSON)); got != string(mimeXML) {
+		t.Errorf("Expected %s, got %s", mimeXML, got)
+	}
+}
@@ -336,7 +336,12 @@ func (sys *BucketTargetSys) getRemoteTargetClient(tcfg *madmin.BucketTarget) (*T
 	config := tcfg.Credentials
 	creds := credentials.NewStaticV4(config.AccessKey, config.SecretKey, "")
 	getRemoteTargetInstanceTransportOnce.Do(func() {
-		getRemoteTargetInstanceTransport = NewRemoteTargetHTTPTransport()
+		// Detect hung targets, the timeout is read for every
+		// request so that config changes apply right away.
+		getRemoteTargetInstanceTransport = responseHeaderTimeoutTransport{
+			RoundTripper: globalTransports.Get(transportRoleRemote),
+			timeout:      globalAPIConfig.getReplicationResponseHeaderTimeout,
+		}
 	})
 
 	api, err := minio.New(tcfg.Endpoint, &miniogo.Options{
@@ -36,9 +36,9 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -59,6 +59,7 @@ import (
 	"github.com/minio/minio/internal/auth"
 	"github.com/minio/minio/internal/color"
 	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/config/api"
 	"github.com/minio/minio/internal/handlers"
 	"github.com/minio/minio/internal/kms"
 	"github.com/minio/minio/internal/logger"
@@ -146,7 +147,7 @@ func init() {
 	defaultAWSCredProvider = []credentials.Provider{
 		&credentials.IAM{
 			Client: &http.Client{
-				Transport: NewGatewayHTTPTransport(),
+				Transport: globalTransports.Get(transportRoleGateway),
 			},
 		},
 	}
@@ -157,6 +158,11 @@ func init() {
 		shardDiskTimeDelta = 1 * time.Minute
 	}
 
+	globalProfilerMaxDuration, err = time.ParseDuration(env.Get("_MINIO_PROFILER_MAX_DURATION", "1h"))
+	if err != nil {
+		globalProfilerMaxDuration = time.Hour
+	}
+
 	// All minio-go API operations shall be performed only once,
 	// another way to look at this is we are turning off retries.
 	minio.MaxRetry = 1
@@ -629,6 +635,24 @@ func loadEnvVarsFromFiles() {
 	}
 }
 
+// normalizeDomainNames - returns the sorted list of unique domains,
+// lowercased and without trailing dots. Empty or invalid domains are
+// rejected.
+func normalizeDomainNames(domains []string) ([]string, error) {
+	normalized := set.NewStringSet()
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if strings.TrimSuffix(domain, ".") == "" {
+			return nil, config.ErrInvalidDomainValue(nil).Msg("Empty value in `%s`", strings.Join(domains, config.ValueSeparator))
+		}
+		if _, ok := dns2.IsDomainName(domain); !ok {
+			return nil, config.ErrInvalidDomainValue(nil).Msg("Unknown value `%s`", domain)
+		}
+		normalized.Add(strings.TrimSuffix(domain, "."))
+	}
+	return normalized.ToSlice(), nil
+}
+
 func handleCommonEnvVars() {
 	loadEnvVarsFromFiles()
 
@@ -675,6 +699,24 @@ func handleCommonEnvVars() {
 		logger.Fatal(config.ErrInvalidFSOSyncValue(err), "Invalid MINIO_FS_OSYNC value in environment variable")
 	}
 
+	globalConfigProbeEndpoints, err = config.ParseBool(env.Get(config.EnvConfigProbeEndpoints, config.EnableOff))
+	if err != nil {
+		logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", config.EnvConfigProbeEndpoints))
+	}
+	globalConfigProbeEndpointsStrict, err = config.ParseBool(env.Get(config.EnvConfigProbeEndpointsStrict, config.EnableOff))
+	if err != nil {
+		logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", config.EnvConfigProbeEndpointsStrict))
+	}
+	globalCredentialsStrict, err = config.ParseBool(env.Get(config.EnvCredentialsStrict, config.EnableOff))
+	if err != nil {
+		logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", config.EnvCredentialsStrict))
+	}
+
+	// The config is not loaded yet, only the environment is checked.
+	if err = config.New().ValidateEncryptionConsistency(); err != nil {
+		logger.Fatal(err, "Invalid KMS configuration in environment variables")
+	}
+
 	if rootDiskSize := env.Get(config.EnvRootDiskThresholdSize, ""); rootDiskSize != "" {
 		size, err := humanize.ParseBytes(rootDiskSize)
 		if err != nil {
@@ -683,16 +725,22 @@ func handleCommonEnvVars() {
 		globalRootDiskThreshold = size
 	}
 
+	// Transports and the KES client are created before the config is
+	// loaded, read the environment variable before building them.
+	if cacheSize := env.Get(api.EnvAPITLSSessionCacheSize, ""); cacheSize != "" {
+		size, err := api.ParseTLSSessionCacheSize(cacheSize)
+		if err != nil {
+			logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", api.EnvAPITLSSessionCacheSize))
+		}
+		atomic.StoreInt64(&tlsClientSessionCacheSize, int64(size))
+	}
+
 	domains := env.Get(config.EnvDomain, "")
 	if len(domains) != 0 {
-		for _, domainName := range strings.Split(domains, config.ValueSeparator) {
-			if _, ok := dns2.IsDomainName(domainName); !ok {
-				logger.Fatal(config.ErrInvalidDomainValue(nil).Msg("Unknown value `%s`", domainName),
-					"Invalid MINIO_DOMAIN value in environment variable")
-			}
-			globalDomainNames = append(globalDomainNames, domainName)
+		globalDomainNames, err = normalizeDomainNames(strings.Split(domains, config.ValueSeparator))
+		if err != nil {
+			logger.Fatal(err, "Invalid MINIO_DOMAIN value in environment variable")
 		}
-		sort.Strings(globalDomainNames)
 		lcpSuf := lcpSuffix(globalDomainNames)
 		for _, domainName := range globalDomainNames {
 			if domainName == lcpSuf && len(globalDomainNames) > 1 {
@@ -855,6 +903,8 @@ func handleKMSConfig() {
 			DefaultKeyID: defaultKeyID,
 			Certificate:  certificate,
 			RootCAs:      rootCAs,
+
+			ClientSessionCache: newTLSSessionCache(),
 		})
 		if err != nil {
 			logger.Fatal(err, "Unable to initialize a connection to KES as specified by the shell environment")
@@ -183,3 +183,35 @@ MINIO_ROOT_PASSWORD=minio123`,
 		})
 	}
 }
+
+func TestNormalizeDomainNames(t *testing.T) {
+	testCases := []struct {
+		domains  []string
+		expected []string
+		wantErr  bool
+	}{
+		{domains: []string{"example.com"}, expected: []string{"example.com"}},
+		{domains: []string{"b.example.com", "a.example.com", "b.example.com"}, expected: []string{"a.example.com", "b.example.com"}},
+		{domains: []string{"Example.COM", "example.com"}, expected: []string{"example.com"}},
+		{domains: []string{"example.com.", "example.com"}, expected: []string{"example.com"}},
+		{domains: []string{"example.com", ""}, wantErr: true},
+		{domains: []string{"."}, wantErr: true},
+		{domains: []string{"example..com"}, wantErr: true},
+	}
+	for i, testCase := range testCases {
+		domains, err := normalizeDomainNames(testCase.domains)
+		if testCase.wantErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for %v", i+1, testCase.domains)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(domains, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, domains)
+		}
+	}
+}
@@ -19,12 +19,17 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/minio/madmin-go"
+	"github.com/minio/minio/internal/color"
 	"github.com/minio/minio/internal/config"
 	"github.com/minio/minio/internal/config/api"
 	"github.com/minio/minio/internal/config/cache"
@@ -44,9 +49,11 @@ import (
 	"github.com/minio/minio/internal/config/storageclass"
 	"github.com/minio/minio/internal/config/subnet"
 	"github.com/minio/minio/internal/crypto"
+	"github.com/minio/minio/internal/event/target"
 	xhttp "github.com/minio/minio/internal/http"
 	"github.com/minio/minio/internal/kms"
 	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/sync/errgroup"
 	"github.com/minio/pkg/env"
 )
 
@@ -339,7 +346,7 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 		}
 	case config.IdentityOpenIDSubSys:
 		if _, err := openid.LookupConfig(s,
-			NewGatewayHTTPTransport(), xhttp.DrainBody, globalSite.Region); err != nil {
+			gatewayTransport(), xhttp.DrainBody, globalSite.Region); err != nil {
 			return err
 		}
 	case config.IdentityLDAPSubSys:
@@ -360,7 +367,7 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 		}
 	case config.IdentityPluginSubSys:
 		if _, err := idplugin.LookupConfig(s[config.IdentityPluginSubSys][config.Default],
-			NewGatewayHTTPTransport(), xhttp.DrainBody, globalSite.Region); err != nil {
+			gatewayTransport(), xhttp.DrainBody, globalSite.Region); err != nil {
 			return err
 		}
 	case config.SubnetSubSys:
@@ -378,12 +385,12 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 		fallthrough
 	case config.PolicyPluginSubSys:
 		if ppargs, err := polplugin.LookupConfig(s[config.PolicyPluginSubSys][config.Default],
-			NewGatewayHTTPTransport(), xhttp.DrainBody); err != nil {
+			gatewayTransport(), xhttp.DrainBody); err != nil {
 			return err
 		} else if ppargs.URL == nil {
 			// Check if legacy opa is configured.
 			if _, err := opa.LookupConfig(s[config.PolicyOPASubSys][config.Default],
-				NewGatewayHTTPTransport(), xhttp.DrainBody); err != nil {
+				gatewayTransport(), xhttp.DrainBody); err != nil {
 				return err
 			}
 		}
@@ -396,16 +403,95 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 	}
 
 	if config.NotifySubSystems.Contains(subSys) {
-		if err := notify.TestSubSysNotificationTargets(GlobalContext, s, NewGatewayHTTPTransport(), globalNotificationSys.ConfiguredTargetIDs(), subSys); err != nil {
+		if err := notify.TestSubSysNotificationTargets(GlobalContext, s, gatewayTransport(), globalNotificationSys.ConfiguredTargetIDs(), subSys); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// endpointProbeTimeout - how long probeConfigEndpoints waits for all
+// endpoints of a sub-system.
+const endpointProbeTimeout = 5 * time.Second
+
+// probeConfigEndpoints - probes the endpoints of all enabled webhook
+// targets of subSys in parallel, unreachable endpoints are logged unless
+// strict is set in which case the first failure is returned.
+func probeConfigEndpoints(ctx context.Context, s config.Config, subSys string, strict bool) error {
+	switch subSys {
+	case config.NotifyWebhookSubSys, config.LoggerWebhookSubSys, config.AuditWebhookSubSys:
+	default:
+		return nil
+	}
+
+	var targets []string
+	for tgt, kvs := range s[subSys] {
+		if enabled, err := config.ParseBool(kvs.Get(config.Enable)); err != nil || !enabled {
+			continue
+		}
+		if kvs.Get(target.WebhookEndpoint) == "" {
+			continue
+		}
+		targets = append(targets, tgt)
+	}
+	sort.Strings(targets)
+
+	ctx, cancel := context.WithTimeout(ctx, endpointProbeTimeout)
+	defer cancel()
+
+	g := errgroup.WithNErrs(len(targets))
+	for index := range targets {
+		index := index
+		g.Go(func() error {
+			return probeTargetEndpoint(ctx, s[subSys][targets[index]])
+		}, index)
+	}
+	for index, err := range g.Wait() {
+		if err == nil {
+			continue
+		}
+		if strict {
+			return config.Errorf("%s target %s: %v", subSys, targets[index], err)
+		}
+		logger.LogIf(ctx, fmt.Errorf("%s target %s endpoint may be misconfigured: %w", subSys, targets[index], err))
+	}
+	return nil
+}
+
+// probeTargetEndpoint - probes the endpoint of a webhook target with
+// the client certificate the target is configured with, if any.
+func probeTargetEndpoint(ctx context.Context, kvs config.KVS) error {
+	endpoint := kvs.Get(target.WebhookEndpoint)
+
+	// All webhook sub-systems share the client certificate keys.
+	clientCert, clientKey := kvs.Get(target.WebhookClientCert), kvs.Get(target.WebhookClientKey)
+	if clientCert == "" || clientKey == "" {
+		return probeEndpoint(ctx, endpoint, endpointProbeTimeout)
+	}
+	cert, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	if err != nil {
+		return fmt.Errorf("unable to load client key and cert: %w", err)
+	}
+	tr := newGatewayHTTPTransport(endpointProbeTimeout)
+	defer tr.CloseIdleConnections()
+	tr.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	return probeEndpointWithTransport(ctx, endpoint, endpointProbeTimeout, tr)
+}
+
 func validateConfig(s config.Config, subSys string) error {
 	objAPI := newObjectLayerFn()
 
+	// Validate with the valueFrom: references resolved, this needs the
+	// env to be on to find the directory they may point into.
+	var subSystems []string
+	if subSys != "" {
+		subSystems = append(subSystems, subSys)
+	}
+	s, err := s.ResolveValueFrom(subSystems...)
+	if err != nil {
+		return err
+	}
+
 	// We must have a global lock for this so nobody else modifies env while we do.
 	defer env.LockSetEnv()()
 
@@ -428,18 +514,35 @@ func validateConfig(s config.Config, subSys string) error {
 	return nil
 }
 
-func lookupConfigs(s config.Config, objAPI ObjectLayer) {
+func lookupConfigs(cfg config.Config, objAPI ObjectLayer) {
 	ctx := GlobalContext
 
-	var err error
+	// Lookup with the valueFrom: references resolved, cfg itself is
+	// not modified so that only the references are ever saved.
+	s, err := cfg.ResolveValueFrom()
+	if err != nil {
+		if globalIsGateway {
+			logger.FatalIf(err, "Unable to resolve config values")
+		} else {
+			logger.LogIf(ctx, fmt.Errorf("Unable to resolve config values: %w", err))
+		}
+	}
+
 	if !globalActiveCred.IsValid() {
 		// Env doesn't seem to be set, we fallback to lookup creds from the config.
 		globalActiveCred, err = config.LookupCreds(s[config.CredentialsSubSys][config.Default])
 		if err != nil {
+			if errors.Is(err, config.ErrDefaultCredentials) {
+				logger.Fatal(err, "Unable to start the server")
+			}
 			logger.LogIf(ctx, fmt.Errorf("Invalid credentials configuration: %w", err))
 		}
 	}
 
+	if err = s.ValidateEncryptionConsistency(); err != nil {
+		logger.LogIf(ctx, fmt.Errorf("Inconsistent encryption configuration: %w", err))
+	}
+
 	dnsURL, dnsUser, dnsPass, err := env.LookupEnv(config.EnvDNSWebhook)
 	if err != nil {
 		if globalIsGateway {
@@ -521,6 +624,10 @@ func lookupConfigs(s config.Config, objAPI ObjectLayer) {
 	apiConfig, err := api.LookupConfig(s[config.APISubSys][config.Default])
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Invalid api configuration: %w", err))
+	} else {
+		// Transports built before the config was loaded follow
+		// the new size, see tlsSessionCache.
+		atomic.StoreInt64(&tlsClientSessionCacheSize, int64(apiConfig.TLSSessionCacheSize))
 	}
 
 	// Initialize remote instance transport once.
@@ -561,7 +668,7 @@ func lookupConfigs(s config.Config, objAPI ObjectLayer) {
 	}
 
 	globalOpenIDConfig, err = openid.LookupConfig(s,
-		NewGatewayHTTPTransport(), xhttp.DrainBody, globalSite.Region)
+		gatewayTransport(), xhttp.DrainBody, globalSite.Region)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize OpenID: %w", err))
 	}
@@ -573,20 +680,20 @@ func lookupConfigs(s config.Config, objAPI ObjectLayer) {
 	}
 
 	authNPluginCfg, err := idplugin.LookupConfig(s[config.IdentityPluginSubSys][config.Default],
-		NewGatewayHTTPTransport(), xhttp.DrainBody, globalSite.Region)
+		gatewayTransport(), xhttp.DrainBody, globalSite.Region)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize AuthNPlugin: %w", err))
 	}
 	globalAuthNPlugin = idplugin.New(authNPluginCfg)
 
 	authZPluginCfg, err := polplugin.LookupConfig(s[config.PolicyPluginSubSys][config.Default],
-		NewGatewayHTTPTransport(), xhttp.DrainBody)
+		gatewayTransport(), xhttp.DrainBody)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize AuthZPlugin: %w", err))
 	}
 	if authZPluginCfg.URL == nil {
 		opaCfg, err := opa.LookupConfig(s[config.PolicyOPASubSys][config.Default],
-			NewGatewayHTTPTransport(), xhttp.DrainBody)
+			gatewayTransport(), xhttp.DrainBody)
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to initialize AuthZPlugin from legacy OPA config: %w", err))
 		} else {
@@ -603,19 +710,22 @@ func lookupConfigs(s config.Config, objAPI ObjectLayer) {
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to parse subnet configuration: %w", err))
 	}
+	for _, warning := range subnet.DeprecationWarnings(s[config.SubnetSubSys][config.Default]) {
+		logger.Info(color.RedBold("WARNING: " + warning))
+	}
 
-	globalConfigTargetList, err = notify.GetNotificationTargets(GlobalContext, s, NewGatewayHTTPTransport(), false)
+	globalConfigTargetList, err = notify.GetNotificationTargets(GlobalContext, s, gatewayTransport(), false)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize notification target(s): %w", err))
 	}
 
-	globalEnvTargetList, err = notify.GetNotificationTargets(GlobalContext, newServerConfig(), NewGatewayHTTPTransport(), true)
+	globalEnvTargetList, err = notify.GetNotificationTargets(GlobalContext, newServerConfig(), gatewayTransport(), true)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize notification target(s): %w", err))
 	}
 
 	// Apply dynamic config values
-	if err := applyDynamicConfig(ctx, objAPI, s); err != nil {
+	if err := applyDynamicConfig(ctx, objAPI, cfg); err != nil {
 		if globalIsGateway {
 			logger.FatalIf(err, "Unable to initialize dynamic configuration")
 		} else {
@@ -624,7 +734,14 @@ func lookupConfigs(s config.Config, objAPI ObjectLayer) {
 	}
 }
 
-func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s config.Config, subSys string) error {
+func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, cfg config.Config, subSys string) error {
+	// Lookup with the valueFrom: references resolved, globalServerConfig
+	// keeps the references.
+	s, err := cfg.ResolveValueFrom(subSys)
+	if err != nil {
+		return err
+	}
+
 	switch subSys {
 	case config.APISubSys:
 		apiConfig, err := api.LookupConfig(s[config.APISubSys][config.Default])
@@ -742,10 +859,16 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		}
 	}
 	globalServerConfigMu.Lock()
-	defer globalServerConfigMu.Unlock()
-	if globalServerConfig != nil {
-		globalServerConfig[subSys] = s[subSys]
+	if globalServerConfig == nil {
+		globalServerConfigMu.Unlock()
+		return nil
 	}
+	old := globalServerConfig[subSys]
+	globalServerConfig[subSys] = cfg[subSys]
+	globalServerConfigMu.Unlock()
+
+	// The change is saved and applied, run the change hooks.
+	config.NotifyChanges(subSys, old, cfg[subSys], config.DefaultKVS[subSys])
 	return nil
 }
 
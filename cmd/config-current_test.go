@@ -19,8 +19,12 @@ package cmd
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/minio/minio/internal/config"
 )
@@ -62,3 +66,94 @@ func TestServerConfig(t *testing.T) {
 		t.Fatalf("Unable to initialize from updated config file %s", err)
 	}
 }
+
+func TestProbeConfigEndpoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	newCfg := func(endpoint string) config.Config {
+		return config.Config{
+			config.NotifyWebhookSubSys: {
+				config.Default: config.KVS{
+					config.KV{Key: config.Enable, Value: config.EnableOn},
+					config.KV{Key: "endpoint", Value: endpoint},
+				},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	reachable := newCfg(srv.URL)
+	unreachable := newCfg("http://" + unreachableEndpoint(t))
+
+	if err := probeConfigEndpoints(ctx, reachable, config.NotifyWebhookSubSys, true); err != nil {
+		t.Fatalf("Expected reachable endpoint to pass, got %v", err)
+	}
+	if err := probeConfigEndpoints(ctx, unreachable, config.NotifyWebhookSubSys, false); err != nil {
+		t.Fatalf("Expected only a warning without strict mode, got %v", err)
+	}
+	err := probeConfigEndpoints(ctx, unreachable, config.NotifyWebhookSubSys, true)
+	if _, ok := err.(config.Error); !ok {
+		t.Fatalf("Expected config.Error in strict mode, got %v", err)
+	}
+
+	// Disabled targets and other sub-systems are not probed.
+	unreachable[config.NotifyWebhookSubSys][config.Default][0].Value = config.EnableOff
+	if err = probeConfigEndpoints(ctx, unreachable, config.NotifyWebhookSubSys, true); err != nil {
+		t.Fatalf("Expected disabled target to be skipped, got %v", err)
+	}
+	if err = probeConfigEndpoints(ctx, newCfg("http://"+unreachableEndpoint(t)), config.APISubSys, true); err != nil {
+		t.Fatalf("Expected %s to be skipped, got %v", config.APISubSys, err)
+	}
+
+	// Client certificates of the target are used, failing to load
+	// them fails the probe.
+	withCert := newCfg(srv.URL)
+	withCert[config.NotifyWebhookSubSys][config.Default] = append(withCert[config.NotifyWebhookSubSys][config.Default],
+		config.KV{Key: "client_cert", Value: "/nonexistent/public.crt"},
+		config.KV{Key: "client_key", Value: "/nonexistent/private.key"})
+	if err = probeConfigEndpoints(ctx, withCert, config.NotifyWebhookSubSys, true); err == nil {
+		t.Fatal("Expected missing client certificate to fail")
+	}
+}
+
+func TestProbeConfigEndpointsParallel(t *testing.T) {
+	const numTargets = 3
+
+	// Every request waits for all of them to arrive, which only
+	// happens if the targets are probed in parallel.
+	var arrived sync.WaitGroup
+	arrived.Add(numTargets)
+	allArrived := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(allArrived)
+	}()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			return
+		}
+		arrived.Done()
+		select {
+		case <-allArrived:
+		case <-time.After(endpointProbeTimeout):
+		}
+	}))
+	defer srv.Close()
+
+	cfg := config.Config{config.NotifyWebhookSubSys: map[string]config.KVS{}}
+	for _, tgt := range []string{"1", "2", "3"} {
+		cfg[config.NotifyWebhookSubSys][tgt] = config.KVS{
+			config.KV{Key: config.Enable, Value: config.EnableOn},
+			config.KV{Key: "endpoint", Value: srv.URL},
+		}
+	}
+	if err := probeConfigEndpoints(context.Background(), cfg, config.NotifyWebhookSubSys, true); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-allArrived:
+	default:
+		t.Fatal("Expected all targets to be probed in parallel")
+	}
+}
@@ -354,6 +354,10 @@ func (es *erasureSingle) IsTaggingSupported() bool {
 func (es *erasureSingle) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (oi ObjectInfo, err error) {
 	defer NSUpdated(dstBucket, dstObject)
 
+	if err := checkObjectNameForLengthAndSlash(dstBucket, dstObject); err != nil {
+		return ObjectInfo{}, err
+	}
+
 	srcObject = encodeDirObject(srcObject)
 	dstObject = encodeDirObject(dstObject)
 
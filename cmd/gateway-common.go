@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
@@ -35,9 +36,6 @@ import (
 )
 
 var (
-	// CanonicalizeETag provides canonicalizeETag function alias.
-	CanonicalizeETag = canonicalizeETag
-
 	// MustGetUUID function alias.
 	MustGetUUID = mustGetUUID
 
@@ -330,6 +328,10 @@ func ErrorRespToObjectError(err error, params ...string) error {
 		err = InvalidUploadID{}
 	case "EntityTooSmall":
 		err = PartTooSmall{}
+	default:
+		// Codes not known to MinIO, such as the ones of other
+		// S3 compatible backends, are translated by status.
+		err = backendErrToObjectError(minioErr, bucket, object)
 	}
 
 	switch minioErr.StatusCode {
@@ -341,6 +343,63 @@ func ErrorRespToObjectError(err error, params ...string) error {
 	return err
 }
 
+// backendErrToObjectError - converts a backend error response with the
+// help of backendStatusToS3Error, the error response is returned as is
+// if it cannot be translated.
+func backendErrToObjectError(errResp minio.ErrorResponse, bucket, object string) error {
+	switch err := backendStatusToS3Error(errResp.StatusCode, errResp.Code).(type) {
+	case ObjectNotFound:
+		if object == "" {
+			return BucketNotFound{Bucket: bucket}
+		}
+		return ObjectNotFound{Bucket: bucket, Object: object}
+	case BucketNotFound:
+		return BucketNotFound{Bucket: bucket}
+	case PrefixAccessDenied:
+		return PrefixAccessDenied{Bucket: bucket, Object: object}
+	case BackendDown:
+		return err
+	default:
+		if err == errBackendSlowDown {
+			return err
+		}
+	}
+	return errResp
+}
+
+// backendStatusToS3Error - converts the HTTP status code and the provider
+// specific error code returned by a gateway backend into an object error,
+// the backend code takes precedence where known. Returns nil for
+// successful status codes.
+func backendStatusToS3Error(statusCode int, backendCode string) error {
+	switch backendCode {
+	case "NoSuchKey", "BlobNotFound", "ResourceNotFound", "notFound":
+		return ObjectNotFound{}
+	case "NoSuchBucket", "ContainerNotFound":
+		return BucketNotFound{}
+	case "AccessDenied", "AuthorizationFailure", "AuthorizationPermissionMismatch", "forbidden":
+		return PrefixAccessDenied{}
+	case "SlowDown", "ServerBusy", "rateLimitExceeded", "TooManyRequests":
+		return errBackendSlowDown
+	}
+
+	switch {
+	case statusCode < http.StatusBadRequest:
+		return nil
+	case statusCode == http.StatusNotFound:
+		return ObjectNotFound{}
+	case statusCode == http.StatusForbidden, statusCode == http.StatusUnauthorized:
+		return PrefixAccessDenied{}
+	case statusCode == http.StatusServiceUnavailable, statusCode == http.StatusTooManyRequests:
+		return errBackendSlowDown
+	case statusCode == http.StatusMethodNotAllowed:
+		return errMethodNotAllowed
+	case statusCode == http.StatusBadGateway, statusCode == http.StatusGatewayTimeout:
+		return BackendDown{Err: http.StatusText(statusCode)}
+	}
+	return fmt.Errorf("backend returned %d %s", statusCode, backendCode)
+}
+
 // ComputeCompleteMultipartMD5 calculates MD5 ETag for complete multipart responses
 func ComputeCompleteMultipartMD5(parts []CompletePart) string {
 	return getCompleteMultipartMD5(parts)
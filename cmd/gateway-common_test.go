@@ -18,8 +18,11 @@
 package cmd
 
 import (
+	"net/http"
 	"reflect"
 	"testing"
+
+	minio "github.com/minio/minio-go/v7"
 )
 
 // Tests cache exclude parsing.
@@ -52,3 +55,60 @@ func TestParseGatewaySSE(t *testing.T) {
 		}
 	}
 }
+
+func TestBackendStatusToS3Error(t *testing.T) {
+	testCases := []struct {
+		statusCode  int
+		backendCode string
+		expected    error
+	}{
+		{http.StatusOK, "", nil},
+		{http.StatusNotFound, "", ObjectNotFound{}},
+		{http.StatusNotFound, "NoSuchBucket", BucketNotFound{}},
+		{http.StatusNotFound, "BlobNotFound", ObjectNotFound{}},
+		{http.StatusForbidden, "", PrefixAccessDenied{}},
+		{http.StatusForbidden, "AuthorizationFailure", PrefixAccessDenied{}},
+		{http.StatusServiceUnavailable, "", errBackendSlowDown},
+		{http.StatusServiceUnavailable, "ServerBusy", errBackendSlowDown},
+		{http.StatusBadGateway, "", BackendDown{Err: http.StatusText(http.StatusBadGateway)}},
+	}
+	for i, testCase := range testCases {
+		if err := backendStatusToS3Error(testCase.statusCode, testCase.backendCode); err != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, err)
+		}
+	}
+
+	if err := backendStatusToS3Error(http.StatusTeapot, "Teapot"); err == nil {
+		t.Error("Expected error for unknown backend failure")
+	}
+	if code := toAPIErrorCode(GlobalContext, backendStatusToS3Error(http.StatusServiceUnavailable, "")); code != ErrSlowDown {
+		t.Errorf("Expected %v, got %v", ErrSlowDown, code)
+	}
+}
+
+func TestErrorRespToObjectErrorBackendStatus(t *testing.T) {
+	testCases := []struct {
+		errResp  minio.ErrorResponse
+		object   string
+		expected error
+	}{
+		{minio.ErrorResponse{StatusCode: http.StatusServiceUnavailable, Code: "SlowDown"}, "object", errBackendSlowDown},
+		{minio.ErrorResponse{StatusCode: http.StatusTooManyRequests, Code: "TooManyRequests"}, "object", errBackendSlowDown},
+		{minio.ErrorResponse{StatusCode: http.StatusNotFound, Code: "BlobNotFound"}, "object", ObjectNotFound{Bucket: "bucket", Object: "object"}},
+		{minio.ErrorResponse{StatusCode: http.StatusNotFound, Code: "ResourceNotFound"}, "", BucketNotFound{Bucket: "bucket"}},
+		{minio.ErrorResponse{StatusCode: http.StatusForbidden, Code: "AuthorizationFailure"}, "object", PrefixAccessDenied{Bucket: "bucket", Object: "object"}},
+		// Known MinIO codes keep their existing translation.
+		{minio.ErrorResponse{StatusCode: http.StatusConflict, Code: "BucketNotEmpty"}, "", BucketNotEmpty{}},
+	}
+	for i, testCase := range testCases {
+		if err := ErrorRespToObjectError(testCase.errResp, "bucket", testCase.object); err != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, err)
+		}
+	}
+
+	// Errors that cannot be translated are returned as is.
+	errResp := minio.ErrorResponse{StatusCode: http.StatusTeapot, Code: "Teapot"}
+	if err, ok := ErrorRespToObjectError(errResp, "bucket").(minio.ErrorResponse); !ok || err.Code != errResp.Code {
+		t.Errorf("Expected %v, got %v", errResp, err)
+	}
+}
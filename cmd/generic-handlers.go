@@ -33,9 +33,11 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/internal/config/dns"
 	"github.com/minio/minio/internal/crypto"
+	"github.com/minio/minio/internal/handlers"
 	xhttp "github.com/minio/minio/internal/http"
 	"github.com/minio/minio/internal/http/stats"
 	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/rest"
 )
 
 const (
@@ -405,7 +407,12 @@ func setRequestValidityHandler(h http.Handler) http.Handler {
 			return
 		}
 		// For all other requests reject access to reserved buckets
-		bucketName, _ := request2BucketObjectName(r)
+		bucketName, _, err := request2BucketObjectNameSafe(r)
+		if err != nil {
+			writeErrorResponse(r.Context(), w, errorCodes.ToAPIErr(ErrBadRequest), r.URL)
+			atomic.AddUint64(&globalHTTPStats.rejectedRequestsInvalid, 1)
+			return
+		}
 		if isMinioReservedBucket(bucketName) || isMinioMetaBucket(bucketName) {
 			if !guessIsRPCReq(r) && !guessIsBrowserReq(r) && !guessIsHealthCheckReq(r) && !guessIsMetricsReq(r) && !isAdminReq(r) {
 				writeErrorResponse(r.Context(), w, errorCodes.ToAPIErr(ErrAllAccessDisabled), r.URL)
@@ -512,6 +519,25 @@ func addCustomHeaders(h http.Handler) http.Handler {
 	})
 }
 
+// setInternodeRequestID keeps the request ID of the calling node on
+// internode requests, so that logs can be correlated across nodes.
+func setInternodeRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := rest.ExtractRequestID(r); requestID != "" {
+			// newContext picks up the request ID from the response header.
+			w.Header().Set(xhttp.AmzRequestID, requestID)
+			r = r.WithContext(logger.SetReqInfo(r.Context(), &logger.ReqInfo{
+				DeploymentID: globalDeploymentID,
+				RequestID:    requestID,
+				RemoteHost:   handlers.GetSourceIP(r),
+				Host:         getHostName(r),
+				UserAgent:    r.UserAgent(),
+			}))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // criticalErrorHandler handles panics and fatal errors by
 // `panic(logger.ErrCritical)` as done by `logger.CriticalIf`.
 //
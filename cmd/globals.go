@@ -20,7 +20,6 @@ package cmd
 import (
 	"crypto/x509"
 	"errors"
-	"net/http"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -125,11 +124,13 @@ const (
 
 	// diskMinInodes is the minimum number of inodes we want free on a disk to perform writes.
 	diskMinInodes = 1000
-
-	// tlsClientSessionCacheSize is the cache size for client sessions.
-	tlsClientSessionCacheSize = 100
 )
 
+// tlsClientSessionCacheSize is the cache size for client sessions, set at
+// startup from the environment and from the api sub-system once the config
+// is loaded. Must be accessed atomically.
+var tlsClientSessionCacheSize int64 = 100
+
 var globalCLIContext = struct {
 	JSON, Quiet    bool
 	Anonymous      bool
@@ -310,7 +311,7 @@ var (
 	standardExcludeCompressExtensions = []string{".gz", ".bz2", ".rar", ".zip", ".7z", ".xz", ".mp4", ".mkv", ".mov", ".jpg", ".png", ".gif"}
 
 	// Some standard content-types which we strictly dis-allow for compression.
-	standardExcludeCompressContentTypes = []string{"video/*", "audio/*", "application/zip", "application/x-gzip", "application/x-zip-compressed", " application/x-compress", "application/x-spoon"}
+	standardExcludeCompressContentTypes = []string{"video/*", "audio/*", "image/jpeg", "image/png", "image/gif", "application/zip", "application/gzip", "application/x-gzip", "application/x-zip-compressed", " application/x-compress", "application/x-spoon"}
 
 	// AuthZ Plugin system.
 	globalAuthZPlugin *polplugin.AuthZPlugin
@@ -332,11 +333,18 @@ var (
 	// If writes to FS backend should be O_SYNC.
 	globalFSOSync bool
 
-	globalProxyEndpoints []ProxyEndpoint
+	// If target endpoints should be probed when their config is set,
+	// probe failures are errors instead of warnings in strict mode.
+	globalConfigProbeEndpoints       bool
+	globalConfigProbeEndpointsStrict bool
 
-	globalInternodeTransport http.RoundTripper
+	// If the default credentials are refused, see config.EnvCredentialsStrict.
+	globalCredentialsStrict bool
+
+	globalProxyEndpoints []ProxyEndpoint
 
-	globalProxyTransport http.RoundTripper
+	// Transports used by outbound calls, selected by their role.
+	globalTransports = newTransportRegistry()
 
 	globalDNSCache = &dnscache.Resolver{
 		Timeout: 5 * time.Second,
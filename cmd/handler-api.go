@@ -19,9 +19,11 @@ package cmd
 
 import (
 	"io/ioutil"
+	"net"
 	"net/http"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -51,6 +53,10 @@ type apiConfig struct {
 	deleteCleanupInterval       time.Duration
 	disableODirect              bool
 	gzipObjects                 bool
+	maxObjectSize               int64
+	proxyAllowedHosts           []string
+
+	replicationResponseHeaderTimeout time.Duration
 }
 
 const cgroupLimitFile = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
@@ -151,6 +157,9 @@ func (t *apiConfig) init(cfg api.Config, setDriveCounts []int) {
 	t.deleteCleanupInterval = cfg.DeleteCleanupInterval
 	t.disableODirect = cfg.DisableODirect
 	t.gzipObjects = cfg.GzipObjects
+	t.maxObjectSize = cfg.MaxObjectSize
+	t.proxyAllowedHosts = cfg.ProxyAllowedHosts
+	t.replicationResponseHeaderTimeout = cfg.ReplicationResponseHeaderTimeout
 }
 
 func (t *apiConfig) isDisableODirect() bool {
@@ -167,6 +176,38 @@ func (t *apiConfig) shouldGzipObjects() bool {
 	return t.gzipObjects
 }
 
+func (t *apiConfig) getMaxObjectSize() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.maxObjectSize <= 0 {
+		return globalMaxObjectSize
+	}
+
+	return t.maxObjectSize
+}
+
+// isProxyHostAllowed returns true if addr, in host or host:port form,
+// is in the proxy allowlist or if the allowlist is empty.
+func (t *apiConfig) isProxyHostAllowed(addr string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.proxyAllowedHosts) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	for _, allowed := range t.proxyAllowedHosts {
+		if strings.EqualFold(allowed, addr) || strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *apiConfig) getListQuorum() string {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -307,3 +348,14 @@ func (t *apiConfig) getTransitionWorkers() int {
 
 	return t.transitionWorkers
 }
+
+func (t *apiConfig) getReplicationResponseHeaderTimeout() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.replicationResponseHeaderTimeout == 0 {
+		return 30 * time.Second // default 30 seconds
+	}
+
+	return t.replicationResponseHeaderTimeout
+}
@@ -20,6 +20,7 @@ package cmd
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"hash"
 
 	"github.com/minio/minio/internal/hash/sha256"
 )
@@ -47,3 +48,25 @@ func getMD5Sum(data []byte) []byte {
 func getMD5Hash(data []byte) string {
 	return hex.EncodeToString(getMD5Sum(data))
 }
+
+// ETagWriter computes the ETag of the data written to it without
+// buffering the data, the zero value is ready to use.
+type ETagWriter struct {
+	md5 hash.Hash
+}
+
+// Write adds p to the running MD5 sum, it never returns an error.
+func (w *ETagWriter) Write(p []byte) (int, error) {
+	if w.md5 == nil {
+		w.md5 = md5.New()
+	}
+	return w.md5.Write(p)
+}
+
+// ETag returns the S3 ETag of all data written so far.
+func (w *ETagWriter) ETag() string {
+	if w.md5 == nil {
+		w.md5 = md5.New()
+	}
+	return ToS3ETag(hex.EncodeToString(w.md5.Sum(nil)))
+}
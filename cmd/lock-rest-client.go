@@ -151,10 +151,10 @@ func newlockRESTClient(endpoint Endpoint) *lockRESTClient {
 		Path:   pathJoin(lockRESTPrefix, lockRESTVersion),
 	}
 
-	restClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+	restClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 	restClient.ExpectTimeouts = true
 	// Use a separate client to avoid recursive calls.
-	healthClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+	healthClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 	healthClient.ExpectTimeouts = true
 	healthClient.NoMetrics = true
 	restClient.HealthCheckFn = func() bool {
@@ -30,6 +30,7 @@ import (
 	"github.com/minio/madmin-go"
 	"github.com/minio/minio/internal/bucket/lifecycle"
 	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/rest"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	dto "github.com/prometheus/client_model/go"
@@ -132,6 +133,7 @@ const (
 	authTotal      MetricName = "auth_total"
 	canceledTotal  MetricName = "canceled_total"
 	errorsTotal    MetricName = "errors_total"
+	errorsRate     MetricName = "errors_rate"
 	headerTotal    MetricName = "header_total"
 	healTotal      MetricName = "heal_total"
 	hitsTotal      MetricName = "hits_total"
@@ -538,6 +540,16 @@ func getInternodeFailedRequests() MetricDescription {
 	}
 }
 
+func getInternodeErrorsRateMD() MetricDescription {
+	return MetricDescription{
+		Namespace: interNodeMetricNamespace,
+		Subsystem: trafficSubsystem,
+		Name:      errorsRate,
+		Help:      "Failed internode calls per second over the last minute, per peer",
+		Type:      gaugeMetric,
+	}
+}
+
 func getInterNodeSentBytesMD() MetricDescription {
 	return MetricDescription{
 		Namespace: interNodeMetricNamespace,
@@ -1613,6 +1625,13 @@ func getNetworkMetrics() *MetricsGroup {
 				Description: getInternodeFailedRequests(),
 				Value:       float64(loadAndResetRPCNetworkErrsCounter()),
 			})
+			for peer, rate := range rest.GetNetworkErrsRates() {
+				metrics = append(metrics, Metric{
+					Description:    getInternodeErrorsRateMD(),
+					Value:          rate,
+					VariableLabels: map[string]string{"peer": peer},
+				})
+			}
 			metrics = append(metrics, Metric{
 				Description: getInterNodeSentBytesMD(),
 				Value:       float64(connStats.TotalOutputBytes),
@@ -296,8 +296,9 @@ func (sys *NotificationSys) StartProfiling(profiler string) []NotificationPeerEr
 	return ng.Wait()
 }
 
-// DownloadProfilingData - download profiling data from all remote peers.
-func (sys *NotificationSys) DownloadProfilingData(ctx context.Context, writer io.Writer) bool {
+// DownloadProfilingData - download profiling data from all remote peers,
+// folded adds folded stacks of the CPU and heap profiles.
+func (sys *NotificationSys) DownloadProfilingData(ctx context.Context, writer io.Writer, folded bool) bool {
 	profilingDataFound := false
 
 	// Initialize a zip writer which will provide a zipped content
@@ -309,7 +310,7 @@ func (sys *NotificationSys) DownloadProfilingData(ctx context.Context, writer io
 		if client == nil {
 			continue
 		}
-		data, err := client.DownloadProfileData()
+		data, err := client.DownloadProfileData(folded)
 		if err != nil {
 			reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
 			ctx := logger.SetReqInfo(ctx, reqInfo)
@@ -359,7 +360,7 @@ func (sys *NotificationSys) DownloadProfilingData(ctx context.Context, writer io
 		return profilingDataFound
 	}
 
-	data, err := getProfileData()
+	data, err := getProfileData(folded)
 	if err != nil {
 		reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", thisAddr.String())
 		ctx := logger.SetReqInfo(ctx, reqInfo)
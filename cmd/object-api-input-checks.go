@@ -187,7 +187,7 @@ func checkPutObjectArgs(ctx context.Context, bucket, object string, obj getBucke
 	if err := checkObjectNameForLengthAndSlash(bucket, object); err != nil {
 		return err
 	}
-	if len(object) == 0 ||
+	if validateObjectName(object) != nil ||
 		!IsValidObjectPrefix(object) {
 		return ObjectNameInvalid{
 			Bucket: bucket,
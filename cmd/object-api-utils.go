@@ -169,6 +169,15 @@ func IsValidObjectPrefix(object string) bool {
 	return true
 }
 
+// validateObjectName rejects empty object names and names containing
+// globalDirSuffix, which encodeDirObject reserves for directory objects.
+func validateObjectName(name string) error {
+	if name == "" || strings.Contains(name, globalDirSuffix) {
+		return ObjectNameInvalid{Object: name}
+	}
+	return nil
+}
+
 // checkObjectNameForLengthAndSlash -check for the validity of object name length and prefis as slash
 func checkObjectNameForLengthAndSlash(bucket, object string) error {
 	// Check for the length of object name
@@ -185,6 +194,13 @@ func checkObjectNameForLengthAndSlash(bucket, object string) error {
 			Object: object,
 		}
 	}
+	// Empty names are rejected by the callers requiring an object name.
+	if object != "" && validateObjectName(object) != nil {
+		return ObjectNameInvalid{
+			Bucket: bucket,
+			Object: object,
+		}
+	}
 	if runtime.GOOS == globalWindowsOSName {
 		// Explicitly disallowed characters on windows.
 		// Avoids most problematic names.
@@ -277,7 +293,7 @@ func removeStandardStorageClass(metadata map[string]string) map[string]string {
 func cleanMetadataKeys(metadata map[string]string, keyNames ...string) map[string]string {
 	newMeta := make(map[string]string, len(metadata))
 	for k, v := range metadata {
-		if contains(keyNames, k) {
+		if containsString(keyNames, k) {
 			continue
 		}
 		newMeta[k] = v
@@ -447,21 +463,7 @@ func excludeForCompression(header http.Header, object string, cfg compress.Confi
 		return true
 	}
 
-	// We strictly disable compression for standard extensions/content-types (`compressed`).
-	if hasStringSuffixInSlice(objStr, standardExcludeCompressExtensions) || hasPattern(standardExcludeCompressContentTypes, contentType) {
-		return true
-	}
-
-	// Filter compression includes.
-	exclude := len(cfg.Extensions) > 0 || len(cfg.MimeTypes) > 0
-	if len(cfg.Extensions) > 0 && hasStringSuffixInSlice(objStr, cfg.Extensions) {
-		exclude = false
-	}
-
-	if len(cfg.MimeTypes) > 0 && hasPattern(cfg.MimeTypes, contentType) {
-		exclude = false
-	}
-	return exclude
+	return !compressibleContentType(contentType, objStr, cfg)
 }
 
 // Utility which returns if a string is present in the list.
@@ -19,11 +19,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/klauspost/compress/s2"
@@ -664,3 +667,87 @@ func TestS2CompressReader(t *testing.T) {
 		})
 	}
 }
+
+// Test checkObjectNameForLengthAndSlash
+func TestCheckObjectNameForLengthAndSlash(t *testing.T) {
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{"object", true},
+		{"dir/object", true},
+		{"dir/", true},
+		{"", true},
+		{"/object", false},
+		{strings.Repeat("a", 1025), false},
+		{"dir" + globalDirSuffix, false},
+		{"dir" + globalDirSuffix + "/object", false},
+	}
+	for i, testCase := range cases {
+		err := checkObjectNameForLengthAndSlash("bucket", testCase.name)
+		if testCase.valid && err != nil {
+			t.Errorf("Case %d: expected %q to be valid, got %v", i, testCase.name, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("Case %d: expected %q to be invalid", i, testCase.name)
+		}
+	}
+}
+
+// Test validateObjectName
+func TestValidateObjectName(t *testing.T) {
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{"object", true},
+		{"dir/object", true},
+		{"dir/", true},
+		{"", false},
+		{"dir" + globalDirSuffix, false},
+		{"dir" + globalDirSuffix + "/object", false},
+	}
+	for i, testCase := range cases {
+		err := validateObjectName(testCase.name)
+		if testCase.valid && err != nil {
+			t.Errorf("Case %d: expected %q to be valid, got %v", i, testCase.name, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("Case %d: expected %q to be invalid", i, testCase.name)
+		}
+	}
+}
+
+// Test that CopyObject on a single drive refuses destinations reserved
+// for directory objects.
+func TestErasureSingleCopyObjectDirSuffix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if _, ok := obj.(*erasureSingle); !ok {
+		t.Fatalf("Expected a single drive object layer, got %T", obj)
+	}
+
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	srcInfo, err := obj.PutObject(ctx, "bucket", "object", mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.CopyObject(ctx, "bucket", "object", "bucket", "dir"+globalDirSuffix, srcInfo, ObjectOptions{}, ObjectOptions{})
+	if _, ok := err.(ObjectNameInvalid); !ok {
+		t.Fatalf("Expected ObjectNameInvalid, got %v", err)
+	}
+	srcInfo.PutObjReader = mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", "")
+	if _, err = obj.CopyObject(ctx, "bucket", "object", "bucket", "copy", srcInfo, ObjectOptions{}, ObjectOptions{}); err != nil {
+		t.Fatalf("Expected a regular destination to be accepted, got %v", err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio/internal/event"
@@ -103,7 +104,7 @@ func checkCopyObjectPreconditions(ctx context.Context, w http.ResponseWriter, r
 	// same as the one specified; otherwise return a 412 (precondition failed).
 	ifMatchETagHeader := r.Header.Get(xhttp.AmzCopySourceIfMatch)
 	if ifMatchETagHeader != "" {
-		if !isETagEqual(objInfo.ETag, ifMatchETagHeader) {
+		if !ETagMatches(ifMatchETagHeader, objInfo.ETag) {
 			// If the object ETag does not match with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL)
@@ -115,7 +116,7 @@ func checkCopyObjectPreconditions(ctx context.Context, w http.ResponseWriter, r
 	// one specified otherwise, return a 304 (not modified).
 	ifNoneMatchETagHeader := r.Header.Get(xhttp.AmzCopySourceIfNoneMatch)
 	if ifNoneMatchETagHeader != "" {
-		if isETagEqual(objInfo.ETag, ifNoneMatchETagHeader) {
+		if ETagMatches(ifNoneMatchETagHeader, objInfo.ETag) {
 			// If the object ETag matches with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL)
@@ -196,7 +197,7 @@ func checkPreconditions(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	// otherwise return a 412 (precondition failed).
 	ifMatchETagHeader := r.Header.Get(xhttp.IfMatch)
 	if ifMatchETagHeader != "" {
-		if !isETagEqual(objInfo.ETag, ifMatchETagHeader) {
+		if !ETagMatches(ifMatchETagHeader, objInfo.ETag) {
 			// If the object ETag does not match with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL)
@@ -208,7 +209,7 @@ func checkPreconditions(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	// one specified otherwise, return a 304 (not modified).
 	ifNoneMatchETagHeader := r.Header.Get(xhttp.IfNoneMatch)
 	if ifNoneMatchETagHeader != "" {
-		if isETagEqual(objInfo.ETag, ifNoneMatchETagHeader) {
+		if ETagMatches(ifNoneMatchETagHeader, objInfo.ETag) {
 			// If the object ETag matches with the specified ETag.
 			writeHeaders()
 			w.WriteHeader(http.StatusNotModified)
@@ -232,10 +233,39 @@ func canonicalizeETag(etag string) string {
 	return etagRegex.ReplaceAllString(etag, "$1")
 }
 
-// isETagEqual return true if the canonical representations of two ETag strings
-// are equal, false otherwise
-func isETagEqual(left, right string) bool {
-	return canonicalizeETag(left) == canonicalizeETag(right)
+// CanonicalizeETag returns ETag with leading and trailing double-quotes
+// removed, if any present
+func CanonicalizeETag(etag string) string {
+	return canonicalizeETag(etag)
+}
+
+// eTagEqualIgnoringSuffix returns true if the ETags a and b are equal,
+// quoted or not, treating the "-1" placeholder ToS3ETag appends to
+// non-checksum ETags as equivalent to its absence. Genuine multipart
+// "-N" suffixes with N > 1 are compared as is.
+func eTagEqualIgnoringSuffix(a, b string) bool {
+	a = strings.TrimSuffix(canonicalizeETag(a), "-1")
+	b = strings.TrimSuffix(canonicalizeETag(b), "-1")
+	return a == b
+}
+
+// ETagMatches returns true if the ETag list sent by the client in an
+// If-Match or If-None-Match header matches the stored ETag. The list may
+// contain several comma separated ETags, quoted or not and with or without
+// the weak validator prefix `W/`. The wildcard `*` matches any stored ETag.
+// A "-1" suffix on either ETag is ignored, see eTagEqualIgnoringSuffix.
+func ETagMatches(clientETag, storedETag string) bool {
+	storedETag = canonicalizeETag(strings.TrimPrefix(strings.TrimSpace(storedETag), "W/"))
+	for _, etag := range strings.Split(clientETag, ",") {
+		etag = strings.TrimSpace(etag)
+		if etag == "*" {
+			return storedETag != ""
+		}
+		if eTagEqualIgnoringSuffix(strings.TrimPrefix(etag, "W/"), storedETag) {
+			return true
+		}
+	}
+	return false
 }
 
 // setPutObjHeaders sets all the necessary headers returned back
@@ -51,3 +51,61 @@ func TestCanonicalizeETag(t *testing.T) {
 		}
 	}
 }
+
+// Tests - eTagEqualIgnoringSuffix()
+func TestETagEqualIgnoringSuffix(t *testing.T) {
+	const etag = "5d57546eeb86b3eba68967292fba0644"
+	testCases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: etag, b: etag, equal: true},
+		{a: etag + "-1", b: etag, equal: true},
+		{a: etag, b: etag + "-1", equal: true},
+		{a: `"` + etag + `-1"`, b: etag, equal: true},
+		{a: etag + "-5", b: etag + "-5", equal: true},
+		{a: etag + "-5", b: etag, equal: false},
+		{a: etag + "-5", b: etag + "-1", equal: false},
+		{a: etag + "-15", b: etag + "-1", equal: false},
+		{a: "8019e762", b: etag, equal: false},
+	}
+	for i, testCase := range testCases {
+		if equal := eTagEqualIgnoringSuffix(testCase.a, testCase.b); equal != testCase.equal {
+			t.Errorf("Test %d: expected %t for %q and %q, got %t", i+1, testCase.equal, testCase.a, testCase.b, equal)
+		}
+	}
+}
+
+// Tests - ETagMatches()
+func TestETagMatches(t *testing.T) {
+	const storedETag = "5d57546eeb86b3eba68967292fba0644-1"
+	testCases := []struct {
+		clientETag string
+		storedETag string
+		matches    bool
+	}{
+		// Plain and quoted.
+		{clientETag: storedETag, storedETag: storedETag, matches: true},
+		{clientETag: `"` + storedETag + `"`, storedETag: storedETag, matches: true},
+		{clientETag: storedETag, storedETag: `"` + storedETag + `"`, matches: true},
+		// Weak validator.
+		{clientETag: `W/"` + storedETag + `"`, storedETag: storedETag, matches: true},
+		// Wildcard.
+		{clientETag: "*", storedETag: storedETag, matches: true},
+		{clientETag: "*", storedETag: "", matches: false},
+		// List of ETags.
+		{clientETag: `"abc", W/"` + storedETag + `"`, storedETag: storedETag, matches: true},
+		{clientETag: `"abc", "def"`, storedETag: storedETag, matches: false},
+		// Without the "-1" placeholder.
+		{clientETag: `"5d57546eeb86b3eba68967292fba0644"`, storedETag: storedETag, matches: true},
+		// Mismatch.
+		{clientETag: `"5d57546eeb86b3eba68967292fba0644-5"`, storedETag: storedETag, matches: false},
+		{clientETag: `"8019e762"`, storedETag: storedETag, matches: false},
+		{clientETag: `W/"8019e762"`, storedETag: storedETag, matches: false},
+	}
+	for i, testCase := range testCases {
+		if matches := ETagMatches(testCase.clientETag, testCase.storedETag); matches != testCase.matches {
+			t.Fatalf("Test %d: expected %t, got %t", i+1, testCase.matches, matches)
+		}
+	}
+}
@@ -216,9 +216,14 @@ func (client *peerRESTClient) StartProfiling(profiler string) error {
 	return nil
 }
 
-// DownloadProfileData - download profiled data from a remote node.
-func (client *peerRESTClient) DownloadProfileData() (data map[string][]byte, err error) {
-	respBody, err := client.call(peerRESTMethodDownloadProfilingData, nil, nil, -1)
+// DownloadProfileData - download profiled data from a remote node, with
+// folded stacks for the CPU and heap profiles if folded is set.
+func (client *peerRESTClient) DownloadProfileData(folded bool) (data map[string][]byte, err error) {
+	values := make(url.Values)
+	if folded {
+		values.Set(peerRESTFolded, "true")
+	}
+	respBody, err := client.call(peerRESTMethodDownloadProfilingData, values, nil, -1)
 	if err != nil {
 		return
 	}
@@ -739,9 +744,9 @@ func newPeerRESTClient(peer *xnet.Host) *peerRESTClient {
 		Path:   peerRESTPath,
 	}
 
-	restClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+	restClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 	// Use a separate client to avoid recursive calls.
-	healthClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+	healthClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 	healthClient.ExpectTimeouts = true
 	healthClient.NoMetrics = true
 
@@ -84,6 +84,7 @@ const (
 	peerRESTSignal         = "signal"
 	peerRESTSubSys         = "sub-sys"
 	peerRESTProfiler       = "profiler"
+	peerRESTFolded         = "folded"
 	peerRESTTraceErr       = "err"
 	peerRESTTraceInternal  = "internal"
 	peerRESTTraceStorage   = "storage"
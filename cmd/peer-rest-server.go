@@ -298,12 +298,10 @@ func (s *peerRESTServer) StartProfilingHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	for _, profiler := range profiles {
-		prof, err := startProfiler(profiler)
-		if err != nil {
+		if err := startAndInstallProfiler(profiler); err != nil {
 			s.writeErrorResponse(w, err)
 			return
 		}
-		globalProfiler[profiler] = prof
 	}
 }
 
@@ -315,7 +313,7 @@ func (s *peerRESTServer) DownloadProfilingDataHandler(w http.ResponseWriter, r *
 	}
 
 	ctx := newContext(r, w, "DownloadProfiling")
-	profileData, err := getProfileData()
+	profileData, err := getProfileData(r.Form.Get(peerRESTFolded) == "true")
 	if err != nil {
 		s.writeErrorResponse(w, err)
 		return
@@ -1270,6 +1268,7 @@ func (s *peerRESTServer) NetSpeedTestHandler(w http.ResponseWriter, r *http.Requ
 func registerPeerRESTHandlers(router *mux.Router) {
 	server := &peerRESTServer{}
 	subrouter := router.PathPrefix(peerRESTPrefix).Subrouter()
+	subrouter.Use(setInternodeRequestID)
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodHealth).HandlerFunc(httpTraceHdrs(server.HealthHandler))
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetLocks).HandlerFunc(httpTraceHdrs(server.GetLocksHandler))
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodServerInfo).HandlerFunc(httpTraceHdrs(server.ServerInfoHandler))
@@ -70,7 +70,7 @@ func selfSpeedtest(ctx context.Context, size, concurrent int, duration time.Dura
 	client, err := minio.New(globalLocalNodeName, &minio.Options{
 		Creds:     credentials.NewStaticV4(globalActiveCred.AccessKey, globalActiveCred.SecretKey, ""),
 		Secure:    globalIsTLS,
-		Transport: globalProxyTransport,
+		Transport: globalTransports.Get(transportRoleProxy),
 		Region:    region,
 	})
 	if err != nil {
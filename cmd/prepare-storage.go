@@ -120,7 +120,7 @@ func isServerResolvable(endpoint Endpoint, timeout time.Duration) error {
 	}
 
 	httpClient := &http.Client{
-		Transport: globalInternodeTransport,
+		Transport: globalTransports.Get(transportRoleInternode),
 	}
 
 	ctx, cancel := context.WithTimeout(GlobalContext, timeout)
@@ -19,7 +19,6 @@ package cmd
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -39,10 +38,8 @@ import (
 	"github.com/minio/minio/internal/bucket/bandwidth"
 	"github.com/minio/minio/internal/color"
 	"github.com/minio/minio/internal/config"
-	"github.com/minio/minio/internal/fips"
 	xhttp "github.com/minio/minio/internal/http"
 	"github.com/minio/minio/internal/logger"
-	"github.com/minio/minio/internal/rest"
 	"github.com/minio/pkg/certs"
 	"github.com/minio/pkg/env"
 )
@@ -203,20 +200,9 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 		}
 	}
 
-	// allow transport to be HTTP/1.1 for proxying.
-	globalProxyTransport = newCustomHTTPProxyTransport(&tls.Config{
-		RootCAs:            globalRootCAs,
-		CipherSuites:       fips.TLSCiphers(),
-		CurvePreferences:   fips.TLSCurveIDs(),
-		ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
-	}, rest.DefaultTimeout)()
+	// The internode and proxy transports are created on first use,
+	// i.e. with the root CAs loaded above.
 	globalProxyEndpoints = GetProxyEndpoints(globalEndpoints)
-	globalInternodeTransport = newInternodeHTTPTransport(&tls.Config{
-		RootCAs:            globalRootCAs,
-		CipherSuites:       fips.TLSCiphers(),
-		CurvePreferences:   fips.TLSCurveIDs(),
-		ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
-	}, rest.DefaultTimeout)()
 
 	// On macOS, if a process already listens on LOCALIPADDR:PORT, net.Listen() falls back
 	// to IPv6 address ie minio will start listening on IPv6 address whereas another
@@ -228,6 +214,9 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 	globalIsDistErasure = (setupType == DistErasureSetupType)
 	if globalIsDistErasure {
 		globalIsErasure = true
+		// Nodes have different hostnames, derive an automatic site
+		// name from the first endpoint which all nodes agree on.
+		config.SetSiteHostname(globalEndpoints[0].Endpoints[0].Hostname())
 	}
 	globalIsErasureSD = (setupType == ErasureSDSetupType)
 }
@@ -466,6 +455,10 @@ func serverMain(ctx *cli.Context) {
 	}()
 
 	if !globalActiveCred.IsValid() && globalIsDistErasure {
+		if globalCredentialsStrict {
+			logger.Fatal(config.ErrDefaultCredentials,
+				fmt.Sprintf("Unable to start the server, 'MINIO_ROOT_USER' and 'MINIO_ROOT_PASSWORD' must be set with %s", config.EnvCredentialsStrict))
+		}
 		globalActiveCred = auth.DefaultCredentials
 	}
 
@@ -650,7 +643,7 @@ func serverMain(ctx *cli.Context) {
 		}
 		for _, v := range os.Environ() {
 			// Do not print sensitive creds in debug.
-			if contains(ks, strings.Split(v, "=")[0]) {
+			if containsString(ks, strings.Split(v, "=")[0]) {
 				continue
 			}
 			logger.Info(v)
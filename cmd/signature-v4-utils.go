@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/minio/minio/internal/auth"
 	"github.com/minio/minio/internal/hash/sha256"
@@ -187,7 +188,7 @@ func extractSignedHeaders(signedHeaders []string, r *http.Request) (http.Header,
 	reqQueries := r.Form
 	// find whether "host" is part of list of signed headers.
 	// if not return ErrUnsignedHeaders. "host" is mandatory.
-	if !contains(signedHeaders, "host") {
+	if !containsString(signedHeaders, "host") {
 		return nil, ErrUnsignedHeaders
 	}
 	extractedSignedHeaders := make(http.Header)
@@ -247,3 +248,17 @@ func signV4TrimAll(input string) string {
 	// unicode.IsSpace() internally here) to one space and return
 	return strings.Join(strings.Fields(input), " ")
 }
+
+// checkPresignedExpiry - validates the validity window of a presigned
+// request signed at signTime for expirySeconds. A signTime ahead of now
+// by at most maxSkew is tolerated. Returns errPresignNotYetValid or
+// errPresignExpired.
+func checkPresignedExpiry(signTime time.Time, expirySeconds int64, now time.Time, maxSkew time.Duration) error {
+	if signTime.After(now.Add(maxSkew)) {
+		return errPresignNotYetValid
+	}
+	if now.Sub(signTime) > time.Duration(expirySeconds)*time.Second {
+		return errPresignExpired
+	}
+	return nil
+}
@@ -342,3 +342,26 @@ func TestGetContentSha256Cksum(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckPresignedExpiry(t *testing.T) {
+	now := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		signTime      time.Time
+		expirySeconds int64
+		err           error
+	}{
+		// Fresh request.
+		{now.Add(-time.Minute), 3600, nil},
+		// Expired request.
+		{now.Add(-2 * time.Hour), 3600, errPresignExpired},
+		// Signed slightly in the future, within skew tolerance.
+		{now.Add(10 * time.Minute), 3600, nil},
+		// Signed beyond skew tolerance.
+		{now.Add(20 * time.Minute), 3600, errPresignNotYetValid},
+	}
+	for i, testCase := range testCases {
+		if err := checkPresignedExpiry(testCase.signTime, testCase.expirySeconds, now, 15*time.Minute); err != testCase.err {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.err, err)
+		}
+	}
+}
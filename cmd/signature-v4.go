@@ -228,11 +228,10 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 
 	// If the host which signed the request is slightly ahead in time (by less than globalMaxSkewTime) the
 	// request should still be allowed.
-	if pSignValues.Date.After(UTCNow().Add(globalMaxSkewTime)) {
+	switch checkPresignedExpiry(pSignValues.Date, int64(pSignValues.Expires/time.Second), UTCNow(), globalMaxSkewTime) {
+	case errPresignNotYetValid:
 		return ErrRequestNotReadyYet
-	}
-
-	if UTCNow().Sub(pSignValues.Date) > pSignValues.Expires {
+	case errPresignExpired:
 		return ErrExpiredPresignRequest
 	}
 
@@ -42,16 +42,6 @@ import (
 	"github.com/tinylib/msgp/msgp"
 )
 
-func isNetworkError(err error) bool {
-	if err == nil {
-		return false
-	}
-	if nerr, ok := err.(*rest.NetworkError); ok {
-		return xnet.IsNetworkOrHostDown(nerr.Err, false)
-	}
-	return false
-}
-
 // Converts network error to storageErr. This function is
 // written so that the storageAPI errors are consistent
 // across network disks.
@@ -60,7 +50,9 @@ func toStorageErr(err error) error {
 		return nil
 	}
 
-	if isNetworkError(err) {
+	// Only internode RPC failures mean the disk is unreachable, other
+	// errors such as short reads are mapped below.
+	if nerr, ok := err.(*rest.NetworkError); ok && xnet.IsNetworkOrHostDown(nerr.Err, false) {
 		return errDiskNotFound
 	}
 
@@ -750,11 +742,11 @@ func newStorageRESTClient(endpoint Endpoint, healthcheck bool) *storageRESTClien
 		Path:   path.Join(storageRESTPrefix, endpoint.Path, storageRESTVersion),
 	}
 
-	restClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+	restClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 
 	if healthcheck {
 		// Use a separate client to avoid recursive calls.
-		healthClient := rest.NewClient(serverURL, globalInternodeTransport, newCachedAuthToken())
+		healthClient := rest.NewClient(serverURL, globalTransports.Get(transportRoleInternode), newCachedAuthToken())
 		healthClient.ExpectTimeouts = true
 		healthClient.NoMetrics = true
 		restClient.HealthCheckFn = func() bool {
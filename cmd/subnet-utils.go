@@ -40,14 +40,19 @@ const (
 func httpClient(timeout time.Duration) *http.Client {
 	return &http.Client{
 		Timeout:   timeout,
-		Transport: globalProxyTransport,
+		Transport: globalTransports.Get(transportRoleProxy),
 	}
 }
 
 func subnetHTTPDo(req *http.Request) (*http.Response, error) {
 	client := httpClient(10 * time.Second)
 	if globalSubnetConfig.ProxyURL != nil {
-		client.Transport.(*http.Transport).Proxy = http.ProxyURL((*url.URL)(globalSubnetConfig.ProxyURL))
+		switch tr := client.Transport.(type) {
+		case *http.Transport:
+			tr.Proxy = http.ProxyURL((*url.URL)(globalSubnetConfig.ProxyURL))
+		case proxyAllowlistTransport:
+			tr.Proxy = http.ProxyURL((*url.URL)(globalSubnetConfig.ProxyURL))
+		}
 	}
 	return client.Do(req)
 }
@@ -113,7 +113,7 @@ func TestMain(m *testing.M) {
 	// Initialize globalConsoleSys system
 	globalConsoleSys = NewConsoleLogger(context.Background())
 
-	globalInternodeTransport = newInternodeHTTPTransport(nil, rest.DefaultTimeout)()
+	globalTransports.Register(transportRoleInternode, newInternodeHTTPTransport(nil, rest.DefaultTimeout))
 
 	initHelp()
 
@@ -36,6 +36,27 @@ var errSizeUnexpected = errors.New("Data size larger than expected")
 // When upload object size is greater than 5G in a single PUT/POST operation.
 var errDataTooLarge = errors.New("Object size larger than allowed limit")
 
+// When an upload would need more parts than the allowed maximum.
+var errTooManyParts = errors.New("Upload requires more parts than allowed limit")
+
+// When a part number is outside the range 1 to globalMaxPartID.
+var errInvalidPartID = errors.New("Part number outside of the allowed range")
+
+// When a presigned request is signed too far in the future.
+var errPresignNotYetValid = errors.New("Presigned request is not yet valid")
+
+// When a presigned request is past its expiry.
+var errPresignExpired = errors.New("Presigned request has expired")
+
+// When a request body is larger than the limit set with limitBody.
+var errRequestBodyTooLarge = errors.New("Request body larger than allowed limit")
+
+// When starting a profiler of a type that is already active.
+var errProfilerAlreadyActive = errors.New("profiler already active")
+
+// When a gateway backend is throttling requests.
+var errBackendSlowDown = errors.New("Backend is throttling requests")
+
 // When upload object size is less than what was expected.
 var errDataTooSmall = errors.New("Object size smaller than expected")
 
@@ -102,3 +123,10 @@ var errIAMNotInitialized = errors.New("IAM sub-system is being initialized, plea
 
 // error returned when upload id not found
 var errUploadIDNotFound = errors.New("Specified Upload ID is not found")
+
+// errNonRetryable - wrap an error with this to stop retryWithBackoff
+// from attempting the operation again.
+var errNonRetryable = errors.New("operation cannot be retried")
+
+// errProxyHostNotAllowed - the destination host is not in the proxy allowlist.
+var errProxyHostNotAllowed = errors.New("host is not allowed as a proxy destination")
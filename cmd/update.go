@@ -20,7 +20,6 @@ package cmd
 import (
 	"bufio"
 	"crypto"
-	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -417,11 +416,8 @@ func getUpdateTransport(timeout time.Duration) http.RoundTripper {
 		IdleConnTimeout:       timeout,
 		TLSHandshakeTimeout:   timeout,
 		ExpectContinueTimeout: timeout,
-		TLSClientConfig: &tls.Config{
-			RootCAs:            globalRootCAs,
-			ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
-		},
-		DisableCompression: true,
+		TLSClientConfig:       newClientTLSConfig(clientTLSOpts{}),
+		DisableCompression:    true,
 	}
 	return updateTransport
 }
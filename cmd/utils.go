@@ -18,38 +18,49 @@
 package cmd
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/md5"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path"
 	"path/filepath"
-	"reflect"
+	"regexp"
 	"runtime"
 	"runtime/pprof"
 	"runtime/trace"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/coreos/go-oidc"
 	"github.com/dustin/go-humanize"
 	"github.com/felixge/fgprof"
+	"github.com/google/pprof/profile"
 	"github.com/gorilla/mux"
 	"github.com/minio/madmin-go"
 	miniogopolicy "github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/minio/internal/color"
 	"github.com/minio/minio/internal/config"
 	"github.com/minio/minio/internal/config/api"
+	"github.com/minio/minio/internal/config/compress"
 	xtls "github.com/minio/minio/internal/config/identity/tls"
 	"github.com/minio/minio/internal/fips"
 	"github.com/minio/minio/internal/handlers"
@@ -105,6 +116,19 @@ func request2BucketObjectName(r *http.Request) (bucketName, objectName string) {
 	return path2BucketObject(path)
 }
 
+// request2BucketObjectNameSafe - same as request2BucketObjectName but
+// returns an error for a malformed virtual-host style request instead
+// of crashing, for callers handling untrusted input.
+func request2BucketObjectNameSafe(r *http.Request) (bucketName, objectName string, err error) {
+	path, err := getResource(r.URL.Path, r.Host, globalDomainNames)
+	if err != nil {
+		return "", "", err
+	}
+
+	bucketName, objectName = path2BucketObject(path)
+	return bucketName, objectName, nil
+}
+
 // path2BucketObjectWithBasePath returns bucket and prefix, if any,
 // of a 'path'. basePath is trimmed from the front of the 'path'.
 func path2BucketObjectWithBasePath(basePath, path string) (bucket, prefix string) {
@@ -130,6 +154,28 @@ func getWriteQuorum(drive int) int {
 	return quorum
 }
 
+// quorumForStorageClass returns the read and write quorum for an erasure
+// set of 'drives' with the given data and parity blocks, write quorum is
+// adjusted the same way as getWriteQuorum.
+func quorumForStorageClass(drives, dataBlocks, parityBlocks int) (readQ, writeQ int, err error) {
+	if drives <= 0 || dataBlocks <= 0 || parityBlocks < 0 {
+		return 0, 0, errInvalidArgument
+	}
+	if dataBlocks+parityBlocks != drives {
+		return 0, 0, fmt.Errorf("data blocks (%d) and parity blocks (%d) must add up to the number of drives (%d)",
+			dataBlocks, parityBlocks, drives)
+	}
+	if parityBlocks > drives/2 {
+		return 0, 0, fmt.Errorf("parity blocks (%d) cannot be more than half the number of drives (%d)",
+			parityBlocks, drives)
+	}
+	readQ, writeQ = dataBlocks, dataBlocks
+	if writeQ == parityBlocks {
+		writeQ++
+	}
+	return readQ, writeQ, nil
+}
+
 // CloneMSS is an exposed function of cloneMSS for gateway usage.
 var CloneMSS = cloneMSS
 
@@ -169,12 +215,68 @@ func xmlDecoder(body io.Reader, v interface{}, size int64) error {
 	return d.Decode(v)
 }
 
+// limitBody - returns r.Body limited to max bytes, reading past
+// max fails with errRequestBodyTooLarge instead of truncating.
+func limitBody(r *http.Request, max int64) io.ReadCloser {
+	return &limitedBody{
+		r:      io.LimitReader(r.Body, max+1),
+		Closer: r.Body,
+		left:   max,
+	}
+}
+
+type limitedBody struct {
+	r io.Reader
+	io.Closer
+	left int64
+}
+
+func (l *limitedBody) Read(p []byte) (n int, err error) {
+	n, err = l.r.Read(p)
+	if int64(n) > l.left {
+		n = int(l.left)
+		l.left = 0
+		return n, errRequestBodyTooLarge
+	}
+	l.left -= int64(n)
+	return n, err
+}
+
 // hasContentMD5 returns true if Content-MD5 header is set.
 func hasContentMD5(h http.Header) bool {
 	_, ok := h[xhttp.ContentMD5]
 	return ok
 }
 
+// setContentMD5 - sets the Content-MD5 header of an outbound request
+// to the base64 encoded MD5 of body, as expected by S3.
+func setContentMD5(req *http.Request, body []byte) {
+	sum := md5.Sum(body)
+	req.Header.Set(xhttp.ContentMD5, base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// setContentMD5Reader - same as setContentMD5 for a body which is not
+// held in memory, body is read once to compute the MD5, rewound and set
+// as the request body along with its length.
+func setContentMD5Reader(req *http.Request, body io.ReadSeeker) error {
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	h := md5.New()
+	n, err := io.Copy(h, body)
+	if err != nil {
+		return err
+	}
+	if _, err = body.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	req.Header.Set(xhttp.ContentMD5, base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	req.Body = io.NopCloser(body)
+	req.ContentLength = n
+	return nil
+}
+
 // http://docs.aws.amazon.com/AmazonS3/latest/dev/UploadingObjects.html
 const (
 	// Maximum object size per PUT request is 5TB.
@@ -197,9 +299,34 @@ const (
 	defaultDialTimeout = 5 * time.Second
 )
 
-// isMaxObjectSize - verify if max object size
+// isMaxObjectSize - verify if max object size, honors the
+// configured api max_object_size override if any.
 func isMaxObjectSize(size int64) bool {
-	return size > globalMaxObjectSize
+	return size > globalAPIConfig.getMaxObjectSize()
+}
+
+// checkObjectSizeLimit - validates an upload before any data is buffered.
+// knownSize is the total object size, negative if it is not known yet
+// (e.g. streaming uploads). maxPartID is the highest part number the upload
+// intends to use, 0 for a single PUT. Returns errDataTooLarge if the object
+// is larger than the effective maximum object size (see isMaxObjectSize,
+// which honors the api max_object_size override) and errTooManyParts if the part count
+// exceeds globalMaxPartID or cannot be satisfied with parts of at least
+// globalMinPartSize.
+func checkObjectSizeLimit(knownSize int64, maxPartID int) error {
+	if knownSize >= 0 && isMaxObjectSize(knownSize) {
+		return errDataTooLarge
+	}
+	if isMaxPartID(maxPartID) {
+		return errTooManyParts
+	}
+	if knownSize >= 0 && maxPartID > 1 {
+		// All parts except the last one must be at least globalMinPartSize.
+		if int64(maxPartID-1)*globalMinPartSize > knownSize {
+			return errTooManyParts
+		}
+	}
+	return nil
 }
 
 // // Check if part size is more than maximum allowed size.
@@ -217,13 +344,58 @@ func isMaxPartID(partID int) bool {
 	return partID > globalMaxPartID
 }
 
-func contains(slice interface{}, elem interface{}) bool {
-	v := reflect.ValueOf(slice)
-	if v.Kind() == reflect.Slice {
-		for i := 0; i < v.Len(); i++ {
-			if v.Index(i).Interface() == elem {
-				return true
-			}
+// isCompressibleContentType - returns true if objects of contentType and
+// ext are worth compressing as per the configured compression extensions
+// and mime types. ext is matched as a suffix, so an object name may be
+// passed as well.
+func isCompressibleContentType(contentType, ext string) bool {
+	globalCompressConfigMu.Lock()
+	cfg := globalCompressConfig
+	globalCompressConfigMu.Unlock()
+
+	return compressibleContentType(contentType, ext, cfg)
+}
+
+func compressibleContentType(contentType, ext string, cfg compress.Config) bool {
+	// Configured mime-types are lower cased, so is the content-type.
+	contentType = strings.ToLower(contentType)
+
+	// We strictly disable compression for standard extensions/content-types (`compressed`).
+	if hasStringSuffixInSlice(ext, standardExcludeCompressExtensions) || hasPattern(standardExcludeCompressContentTypes, contentType) {
+		return false
+	}
+
+	// Filter compression includes.
+	if len(cfg.Extensions) == 0 && len(cfg.MimeTypes) == 0 {
+		return true
+	}
+	return (len(cfg.Extensions) > 0 && hasStringSuffixInSlice(ext, cfg.Extensions)) ||
+		(len(cfg.MimeTypes) > 0 && hasPattern(cfg.MimeTypes, contentType))
+}
+
+// validatePart - validates a part of a multipart upload. Returns
+// errInvalidPartID if partID is out of range, PartTooBig if size exceeds
+// globalMaxPartSize and PartTooSmall if size is below globalMinPartSize,
+// the last part being exempt from the minimum.
+func validatePart(partID int, size int64, isLast bool) error {
+	if partID < 1 || isMaxPartID(partID) {
+		return errInvalidPartID
+	}
+	if isMaxAllowedPartSize(size) {
+		return PartTooBig{}
+	}
+	if !isLast && !isMinAllowedPartSize(size) {
+		return PartTooSmall{PartSize: size, PartNumber: partID}
+	}
+	return nil
+}
+
+// containsString returns true if s is present in slice, unlike a reflect
+// based lookup it does not allocate.
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
 		}
 	}
 	return false
@@ -268,8 +440,10 @@ func (p profilerWrapper) Extension() string {
 }
 
 // Returns current profile data, returns error if there is no active
-// profiling in progress. Stops an active profile.
-func getProfileData() (map[string][]byte, error) {
+// profiling in progress. Stops an active profile. If folded is set the
+// CPU and heap profiles are also returned as folded stacks, as used by
+// flamegraph tools, with the "folded" extension.
+func getProfileData(folded bool) (map[string][]byte, error) {
 	globalProfilerMu.Lock()
 	defer globalProfilerMu.Unlock()
 
@@ -284,7 +458,14 @@ func getProfileData() (map[string][]byte, error) {
 		buf, err := prof.Stop()
 		delete(globalProfiler, typ)
 		if err == nil {
-			dst[typ+"."+prof.Extension()] = buf
+			name := profileDataName(typ)
+			dst[name+"."+prof.Extension()] = buf
+			kind, _, _ := splitProfilerLabel(typ)
+			if folded && (kind == string(madmin.ProfilerCPU) || kind == string(madmin.ProfilerMEM)) {
+				if fbuf, ferr := foldProfile(buf); ferr == nil {
+					dst[name+".folded"] = fbuf
+				}
+			}
 		}
 		for name, buf := range prof.Records() {
 			if len(buf) > 0 {
@@ -295,21 +476,238 @@ func getProfileData() (map[string][]byte, error) {
 	return dst, nil
 }
 
+// profileBundleManifestName - name of the manifest in a profile bundle.
+const profileBundleManifestName = "manifest.json"
+
+// profileBundleManifest - describes the server a profile bundle was
+// captured on.
+type profileBundleManifest struct {
+	Time         time.Time `json:"time"`
+	Mode         string    `json:"mode"`
+	DeploymentID string    `json:"deploymentID"`
+	GoVersion    string    `json:"goVersion"`
+	Profiles     []string  `json:"profiles"`
+}
+
+// ProfileBundle - stops the active profiles and returns them zipped
+// along with a JSON manifest, so the bundle is self-describing when
+// attached to a support request.
+func ProfileBundle() ([]byte, error) {
+	data, err := getProfileData(false)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := profileBundleManifest{
+		Time:         UTCNow(),
+		Mode:         getMinioMode(),
+		DeploymentID: globalDeploymentID,
+		GoVersion:    runtime.Version(),
+		Profiles:     make([]string, 0, len(data)),
+	}
+	for name := range data {
+		manifest.Profiles = append(manifest.Profiles, name)
+	}
+	sort.Strings(manifest.Profiles)
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	writeFile := func(name string, data []byte) error {
+		zwriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: manifest.Time,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = zwriter.Write(data)
+		return err
+	}
+	if err = writeFile(profileBundleManifestName, manifestBytes); err != nil {
+		return nil, err
+	}
+	for _, name := range manifest.Profiles {
+		if err = writeFile(name, data[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// foldProfile - converts a pprof profile into folded stacks, one
+// "outer;...;inner value" line per unique stack using the default
+// sample type of the profile.
+func foldProfile(buf []byte) ([]byte, error) {
+	p, err := profile.ParseData(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.SampleType) == 0 {
+		return nil, errors.New("profile has no sample types")
+	}
+	idx := len(p.SampleType) - 1
+	for i, st := range p.SampleType {
+		if st.Type == p.DefaultSampleType {
+			idx = i
+		}
+	}
+
+	stacks := make(map[string]int64)
+	for _, sample := range p.Sample {
+		value := sample.Value[idx]
+		if value == 0 {
+			continue
+		}
+		var frames []string
+		// Locations are ordered from the leaf, inlined lines
+		// of a location are ordered from the innermost.
+		for i := len(sample.Location) - 1; i >= 0; i-- {
+			lines := sample.Location[i].Line
+			for j := len(lines) - 1; j >= 0; j-- {
+				if lines[j].Function != nil {
+					frames = append(frames, lines[j].Function.Name)
+				}
+			}
+		}
+		if len(frames) == 0 {
+			continue
+		}
+		stacks[strings.Join(frames, ";")] += value
+	}
+
+	keys := make([]string, 0, len(stacks))
+	for stack := range stacks {
+		keys = append(keys, stack)
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	for _, stack := range keys {
+		fmt.Fprintf(&out, "%s %d\n", stack, stacks[stack])
+	}
+	return out.Bytes(), nil
+}
+
 func setDefaultProfilerRates() {
 	runtime.MemProfileRate = 4096      // 512K -> 4K - Must be constant throughout application lifetime.
 	runtime.SetMutexProfileFraction(0) // Disable until needed
-	runtime.SetBlockProfileRate(0)     // Disable until needed
+	setBlockProfileRate(0)             // Disable until needed
+}
+
+// blockProfileRate - the last rate set with setBlockProfileRate, the
+// runtime offers no way to read it back.
+var blockProfileRate int64
+
+// setBlockProfileRate - same as runtime.SetBlockProfileRate but
+// remembers the rate for snapshotProfilerRates.
+func setBlockProfileRate(rate int) {
+	atomic.StoreInt64(&blockProfileRate, int64(rate))
+	runtime.SetBlockProfileRate(rate)
+}
+
+// profilerRates - the block and mutex profiling rates in effect, the
+// memory profile rate must not change once set and is not included.
+// Negative rates are left unchanged by restoreProfilerRates.
+type profilerRates struct {
+	block         int
+	mutexFraction int
+}
+
+// snapshotProfilerRates - returns the profiling rates in effect.
+func snapshotProfilerRates() profilerRates {
+	return profilerRates{
+		block:         int(atomic.LoadInt64(&blockProfileRate)),
+		mutexFraction: runtime.SetMutexProfileFraction(-1),
+	}
+}
+
+// restoreProfilerRates - restores rates taken with snapshotProfilerRates.
+func restoreProfilerRates(rates profilerRates) {
+	if rates.block >= 0 {
+		setBlockProfileRate(rates.block)
+	}
+	if rates.mutexFraction >= 0 {
+		runtime.SetMutexProfileFraction(rates.mutexFraction)
+	}
+}
+
+// profilerTempDir - creates a temporary directory for the profilers
+// writing to files. The directory is created under MINIO_PROFILER_DIR
+// if set, otherwise under the OS temporary directory falling back to
+// the user cache directory, for hosts with a read-only /tmp.
+func profilerTempDir() (string, error) {
+	if dir := env.Get(config.EnvProfilerDir, ""); dir != "" {
+		return makeProfilerTempDir([]string{dir})
+	}
+	dirs := []string{os.TempDir()}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs, cacheDir)
+	}
+	return makeProfilerTempDir(dirs)
+}
+
+func makeProfilerTempDir(dirs []string) (string, error) {
+	for _, dir := range dirs {
+		if dirPath, err := ioutil.TempDir(dir, "profile"); err == nil {
+			return dirPath, nil
+		}
+	}
+	return "", fmt.Errorf("unable to create a temporary directory for the profiler in %s, set %s to a writable directory",
+		strings.Join(dirs, ", "), config.EnvProfilerDir)
+}
+
+// profilerLabelSep separates a profiler type from its optional label,
+// e.g. "trace:upload" so that successive traces are distinguishable.
+const profilerLabelSep = ":"
+
+var profilerLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// splitProfilerLabel - splits profilerType into the profiler type and
+// its optional label, only the trace profiler accepts a label.
+func splitProfilerLabel(profilerType string) (string, string, error) {
+	parts := strings.SplitN(profilerType, profilerLabelSep, 2)
+	if len(parts) != 2 {
+		return profilerType, "", nil
+	}
+	typ, label := parts[0], parts[1]
+	if madmin.ProfilerType(typ) != madmin.ProfilerTrace {
+		return "", "", fmt.Errorf("profiler type %s does not accept a label", typ)
+	}
+	if !profilerLabelRegex.MatchString(label) {
+		return "", "", fmt.Errorf("invalid profiler label %q", label)
+	}
+	return typ, label, nil
+}
+
+// profileDataName - returns the generic name of the profile data
+// of profilerType, the label of a labeled profiler is appended
+// with a '-' like the "before" records.
+func profileDataName(profilerType string) string {
+	return strings.Replace(profilerType, profilerLabelSep, "-", 1)
 }
 
 // Starts a profiler returns nil if profiler is not enabled, caller needs to handle this.
 func startProfiler(profilerType string) (minioProfiler, error) {
+	profilerType, _, err := splitProfilerLabel(profilerType)
+	if err != nil {
+		return nil, err
+	}
+
 	var prof profilerWrapper
 	prof.ext = "pprof"
 	// Enable profiler and set the name of the file that pkg/pprof
 	// library creates to store profiling data.
 	switch madmin.ProfilerType(profilerType) {
 	case madmin.ProfilerCPU:
-		dirPath, err := ioutil.TempDir("", "profile")
+		dirPath, err := profilerTempDir()
 		if err != nil {
 			return nil, err
 		}
@@ -339,7 +737,7 @@ func startProfiler(profilerType string) (minioProfiler, error) {
 		if n := runtime.NumGoroutine(); n > 10000 && !globalIsCICD {
 			return nil, fmt.Errorf("unable to perform CPU IO profile with %d goroutines", n)
 		}
-		dirPath, err := ioutil.TempDir("", "profile")
+		dirPath, err := profilerTempDir()
 		if err != nil {
 			return nil, err
 		}
@@ -371,20 +769,26 @@ func startProfiler(profilerType string) (minioProfiler, error) {
 			return buf.Bytes(), err
 		}
 	case madmin.ProfilerBlock:
-		runtime.SetBlockProfileRate(100)
+		// Only restore the block rate, the mutex profiler may be
+		// started and stopped independently in the meantime.
+		rates := snapshotProfilerRates()
+		rates.mutexFraction = -1
+		setBlockProfileRate(100)
 		prof.stopFn = func() ([]byte, error) {
 			var buf bytes.Buffer
 			err := pprof.Lookup("block").WriteTo(&buf, 0)
-			runtime.SetBlockProfileRate(0)
+			restoreProfilerRates(rates)
 			return buf.Bytes(), err
 		}
 	case madmin.ProfilerMutex:
 		prof.record("mutex", 0, "before")
+		rates := snapshotProfilerRates()
+		rates.block = -1
 		runtime.SetMutexProfileFraction(1)
 		prof.stopFn = func() ([]byte, error) {
 			var buf bytes.Buffer
 			err := pprof.Lookup("mutex").WriteTo(&buf, 0)
-			runtime.SetMutexProfileFraction(0)
+			restoreProfilerRates(rates)
 			return buf.Bytes(), err
 		}
 	case madmin.ProfilerThreads:
@@ -404,7 +808,7 @@ func startProfiler(profilerType string) (minioProfiler, error) {
 			return buf.Bytes(), err
 		}
 	case madmin.ProfilerTrace:
-		dirPath, err := ioutil.TempDir("", "profile")
+		dirPath, err := profilerTempDir()
 		if err != nil {
 			return nil, err
 		}
@@ -448,8 +852,62 @@ type minioProfiler interface {
 var (
 	globalProfiler   map[string]minioProfiler
 	globalProfilerMu sync.Mutex
+
+	// Profilers installed with installProfiler are stopped and removed
+	// from globalProfiler once they have been running this long.
+	globalProfilerMaxDuration = time.Hour
 )
 
+// expiringProfiler - a profiler which stops itself once
+// globalProfilerMaxDuration elapses.
+type expiringProfiler struct {
+	minioProfiler
+	timer *time.Timer
+}
+
+// Stop the profiler along with its expiry.
+func (p *expiringProfiler) Stop() ([]byte, error) {
+	p.timer.Stop()
+	return p.minioProfiler.Stop()
+}
+
+// installProfiler - installs prof as the active profiler of profilerType,
+// so that an abandoned profiling session does not stay enabled forever it
+// is stopped and removed after globalProfilerMaxDuration. Caller must hold
+// globalProfilerMu.
+func installProfiler(profilerType string, prof minioProfiler) {
+	p := &expiringProfiler{minioProfiler: prof}
+	p.timer = time.AfterFunc(globalProfilerMaxDuration, func() {
+		globalProfilerMu.Lock()
+		defer globalProfilerMu.Unlock()
+
+		if cur, ok := globalProfiler[profilerType].(*expiringProfiler); ok && cur == p {
+			p.minioProfiler.Stop()
+			delete(globalProfiler, profilerType)
+		}
+	})
+	globalProfiler[profilerType] = p
+}
+
+// startAndInstallProfiler - starts a profiler of profilerType and installs
+// it, returns errProfilerAlreadyActive instead of enabling the runtime
+// profiling twice if one is already active. Caller must hold
+// globalProfilerMu.
+func startAndInstallProfiler(profilerType string) error {
+	if _, ok := globalProfiler[profilerType]; ok {
+		return errProfilerAlreadyActive
+	}
+	prof, err := startProfiler(profilerType)
+	if err != nil {
+		return err
+	}
+	if globalProfiler == nil {
+		globalProfiler = make(map[string]minioProfiler, 10)
+	}
+	installProfiler(profilerType, prof)
+	return nil
+}
+
 // dump the request into a string in JSON format.
 func dumpRequest(r *http.Request) string {
 	header := r.Header.Clone()
@@ -494,6 +952,20 @@ func GenETag() string {
 	return ToS3ETag(getMD5Hash([]byte(mustGetUUID())))
 }
 
+// GenChecksumETag - generate UUID based ETag using the given checksum
+// algorithm. "md5" returns the same form as GenETag, "sha256" returns
+// a base64 encoded digest as used by x-amz-checksum-sha256.
+func GenChecksumETag(algo string) (string, error) {
+	switch strings.ToLower(algo) {
+	case "md5":
+		return GenETag(), nil
+	case "sha256":
+		return base64.StdEncoding.EncodeToString(getSHA256Sum([]byte(mustGetUUID()))), nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q", algo)
+	}
+}
+
 // ToS3ETag - return checksum to ETag
 func ToS3ETag(etag string) string {
 	etag = canonicalizeETag(etag)
@@ -553,8 +1025,28 @@ func newInternodeHTTPTransport(tlsConfig *tls.Config, dialTimeout time.Duration)
 	}
 }
 
+// proxyAllowlistTransport refuses requests to hosts which are neither
+// part of the cluster nor in the proxy allowlist of the api sub-system.
+// The destination of the request is checked, not the dialed address,
+// which is the address of the proxy when HTTP_PROXY is set.
+type proxyAllowlistTransport struct {
+	*http.Transport
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t proxyAllowlistTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if _, ok := globalRemoteEndpoints[host]; !ok && !globalAPIConfig.isProxyHostAllowed(host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s", errProxyHostNotAllowed, host)
+	}
+	return t.Transport.RoundTrip(req)
+}
+
 // Used by only proxied requests, specifically only supports HTTP/1.1
-func newCustomHTTPProxyTransport(tlsConfig *tls.Config, dialTimeout time.Duration) func() *http.Transport {
+func newCustomHTTPProxyTransport(tlsConfig *tls.Config, dialTimeout time.Duration) func() http.RoundTripper {
 	// For more details about various values used here refer
 	// https://golang.org/pkg/net/http/#Transport documentation
 	tr := &http.Transport{
@@ -575,8 +1067,8 @@ func newCustomHTTPProxyTransport(tlsConfig *tls.Config, dialTimeout time.Duratio
 		DisableCompression: true,
 	}
 
-	return func() *http.Transport {
-		return tr
+	return func() http.RoundTripper {
+		return proxyAllowlistTransport{tr}
 	}
 }
 
@@ -626,6 +1118,159 @@ func newCustomHTTPTransport(tlsConfig *tls.Config, dialTimeout time.Duration) fu
 	}
 }
 
+// transportRole - role of a transport in globalTransports.
+type transportRole int
+
+// Roles of the transports in globalTransports, newTransportRegistry
+// must seed a builder for each of them.
+const (
+	// Calls to the other nodes of the cluster.
+	transportRoleInternode transportRole = iota
+	// Proxied calls to the nodes of the cluster and external services
+	// such as SUBNET, HTTP/1.1 only.
+	transportRoleProxy
+	// Calls to external services such as identity and policy plugins
+	// and notification targets, a new transport per call.
+	transportRoleGateway
+	// Calls to remote replication targets, a new transport per call.
+	transportRoleRemote
+
+	numTransportRoles
+)
+
+// transportRegistry - builders of the transports used by outbound calls
+// keyed by role, so that the transport of a role can be replaced at
+// runtime, e.g. in tests, without changing its callers.
+type transportRegistry struct {
+	mu       sync.RWMutex
+	builders [numTransportRoles]func() http.RoundTripper
+}
+
+func newTransportRegistry() *transportRegistry {
+	return &transportRegistry{
+		builders: [numTransportRoles]func() http.RoundTripper{
+			transportRoleInternode: sharedTransport(newInternodeHTTPTransport),
+			transportRoleProxy:     sharedTransport(newCustomHTTPProxyTransport),
+			transportRoleGateway: func() http.RoundTripper {
+				return NewGatewayHTTPTransport()
+			},
+			transportRoleRemote: func() http.RoundTripper {
+				return NewRemoteTargetHTTPTransport()
+			},
+		},
+	}
+}
+
+// sharedTransport - returns a builder of a single transport shared by
+// all callers. The transport is created on first use, once the root
+// CAs are loaded.
+func sharedTransport(newTransport func(tlsConfig *tls.Config, dialTimeout time.Duration) func() http.RoundTripper) func() http.RoundTripper {
+	var (
+		once  sync.Once
+		build func() http.RoundTripper
+	)
+	return func() http.RoundTripper {
+		once.Do(func() {
+			build = newTransport(newClientTLSConfig(clientTLSOpts{FIPS: true}), rest.DefaultTimeout)
+		})
+		return build()
+	}
+}
+
+// Register - sets build as the builder of the transports of role,
+// replacing any previous one.
+func (r *transportRegistry) Register(role transportRole, build func() http.RoundTripper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.builders[role] = build
+}
+
+// Get - returns a transport of role. Clients such as rest.Client capture
+// the transport when they are created, registering a new builder only
+// affects clients created afterwards.
+func (r *transportRegistry) Get(role transportRole) http.RoundTripper {
+	r.mu.RLock()
+	build := r.builders[role]
+	r.mu.RUnlock()
+	return build()
+}
+
+// gatewayTransport - returns a transport of the gateway role for the
+// callers which require an *http.Transport, falls back to the default
+// gateway transport if another kind of transport is registered.
+func gatewayTransport() *http.Transport {
+	rt := globalTransports.Get(transportRoleGateway)
+	if tr, ok := rt.(*http.Transport); ok {
+		return tr
+	}
+	logger.LogOnceIf(GlobalContext, fmt.Errorf("gateway transport %T is not an *http.Transport, using the default transport", rt), "gateway-transport")
+	return NewGatewayHTTPTransport()
+}
+
+// certReloadWindow - SIGHUPs received within this window after
+// the first one are coalesced into a single reload.
+const certReloadWindow = time.Second
+
+// certReloader - reloads the certificates of all registered managers
+// upon SIGHUP, a burst of signals triggers a single coordinated reload
+// instead of one per manager and signal.
+type certReloader struct {
+	mu       sync.Mutex
+	managers []interface{ ReloadCerts() }
+	window   time.Duration
+	once     sync.Once
+}
+
+var globalCertReloader = &certReloader{window: certReloadWindow}
+
+// register - adds m to the managers reloaded upon SIGHUP, the
+// signal handler is installed with the first registration.
+func (r *certReloader) register(m interface{ ReloadCerts() }) {
+	r.mu.Lock()
+	r.managers = append(r.managers, m)
+	r.mu.Unlock()
+
+	r.once.Do(func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGHUP)
+		go r.run(GlobalContext, sig)
+	})
+}
+
+// run - waits for signals on sig, the first one of a burst starts the
+// window, signals received until it elapses are absorbed and all
+// managers are reloaded once. Signals received during the reload
+// start the next window, so later changes are always picked up.
+func (r *certReloader) run(ctx context.Context, sig <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+		}
+
+		timer := time.NewTimer(r.window)
+	coalesce:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-sig:
+			case <-timer.C:
+				break coalesce
+			}
+		}
+
+		r.mu.Lock()
+		managers := append([]interface{ ReloadCerts() }{}, r.managers...)
+		r.mu.Unlock()
+		for _, m := range managers {
+			m.ReloadCerts()
+		}
+	}
+}
+
 // NewGatewayHTTPTransportWithClientCerts returns a new http configuration
 // used while communicating with the cloud backends.
 func NewGatewayHTTPTransportWithClientCerts(clientCert, clientKey string) *http.Transport {
@@ -640,13 +1285,87 @@ func NewGatewayHTTPTransportWithClientCerts(clientCert, clientKey string) *http.
 		}
 		if c != nil {
 			c.UpdateReloadDuration(10 * time.Second)
-			c.ReloadOnSignal(syscall.SIGHUP) // allow reloads upon SIGHUP
+			globalCertReloader.register(c) // allow reloads upon SIGHUP
 			transport.TLSClientConfig.GetClientCertificate = c.GetClientCertificate
 		}
 	}
 	return transport
 }
 
+// clientTLSOpts - options for newClientTLSConfig.
+type clientTLSOpts struct {
+	// FIPS restricts cipher suites and curves to the ones
+	// returned by the fips package.
+	FIPS bool
+
+	// ServerName overrides the server name sent with SNI
+	// and used to verify the server certificate.
+	ServerName string
+
+	// InsecureSkipVerify disables verification of the server
+	// certificate, only honored if explicitly allowed with
+	// MINIO_TLS_ALLOW_INSECURE_SKIP_VERIFY=on.
+	InsecureSkipVerify bool
+}
+
+// tlsSessionCache is a tls.ClientSessionCache holding up to
+// tlsClientSessionCacheSize sessions. The underlying LRU cache is replaced
+// when the size changes, so that transports built before the config is
+// loaded follow the configured size.
+type tlsSessionCache struct {
+	mu    sync.Mutex
+	size  int64
+	cache tls.ClientSessionCache
+}
+
+func newTLSSessionCache() *tlsSessionCache {
+	return &tlsSessionCache{}
+}
+
+func (c *tlsSessionCache) current() tls.ClientSessionCache {
+	size := atomic.LoadInt64(&tlsClientSessionCacheSize)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache == nil || c.size != size {
+		c.cache = tls.NewLRUClientSessionCache(int(size))
+		c.size = size
+	}
+	return c.cache
+}
+
+// Get returns the session for sessionKey, if any.
+func (c *tlsSessionCache) Get(sessionKey string) (*tls.ClientSessionState, bool) {
+	return c.current().Get(sessionKey)
+}
+
+// Put adds cs to the cache with sessionKey.
+func (c *tlsSessionCache) Put(sessionKey string, cs *tls.ClientSessionState) {
+	c.current().Put(sessionKey, cs)
+}
+
+// newClientTLSConfig returns the TLS config for outbound connections.
+func newClientTLSConfig(opts clientTLSOpts) *tls.Config {
+	tlsConfig := &tls.Config{
+		RootCAs:            globalRootCAs,
+		ServerName:         opts.ServerName,
+		ClientSessionCache: newTLSSessionCache(),
+	}
+	if opts.FIPS {
+		tlsConfig.CipherSuites = fips.TLSCiphers()
+		tlsConfig.CurvePreferences = fips.TLSCurveIDs()
+	}
+	if opts.InsecureSkipVerify {
+		if env.Get(config.EnvTLSAllowInsecureSkipVerify, config.EnableOff) == config.EnableOn {
+			logger.Info(color.RedBold("WARNING: TLS certificate verification is disabled for outbound connections, connections are open to man-in-the-middle attacks"))
+			tlsConfig.InsecureSkipVerify = true
+		} else {
+			logger.LogIf(GlobalContext, fmt.Errorf("refusing to skip TLS certificate verification, set %s=on to allow it",
+				config.EnvTLSAllowInsecureSkipVerify))
+		}
+	}
+	return tlsConfig
+}
+
 // NewGatewayHTTPTransport returns a new http configuration
 // used while communicating with the cloud backends.
 func NewGatewayHTTPTransport() *http.Transport {
@@ -654,10 +1373,7 @@ func NewGatewayHTTPTransport() *http.Transport {
 }
 
 func newGatewayHTTPTransport(timeout time.Duration) *http.Transport {
-	tr := newCustomHTTPTransport(&tls.Config{
-		RootCAs:            globalRootCAs,
-		ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
-	}, defaultDialTimeout)()
+	tr := newCustomHTTPTransport(newClientTLSConfig(clientTLSOpts{}), defaultDialTimeout)()
 
 	// Customize response header timeout for gateway transport.
 	tr.ResponseHeaderTimeout = timeout
@@ -681,10 +1397,7 @@ func NewRemoteTargetHTTPTransport() *http.Transport {
 		IdleConnTimeout:       15 * time.Second,
 		TLSHandshakeTimeout:   5 * time.Second,
 		ExpectContinueTimeout: 5 * time.Second,
-		TLSClientConfig: &tls.Config{
-			RootCAs:            globalRootCAs,
-			ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
-		},
+		TLSClientConfig:       newClientTLSConfig(clientTLSOpts{}),
 		// Go net/http automatically unzip if content-type is
 		// gzip disable this feature, as we are always interested
 		// in raw stream.
@@ -693,6 +1406,51 @@ func NewRemoteTargetHTTPTransport() *http.Transport {
 	return tr
 }
 
+// errResponseHeaderTimeout - returned by responseHeaderTimeoutTransport
+// when the response headers are not received in time.
+var errResponseHeaderTimeout = errors.New("timeout awaiting response headers")
+
+// responseHeaderTimeoutTransport - limits the wait for the response
+// headers to timeout, which is called for every request so that config
+// changes apply right away. Only the wait for the response headers is
+// limited and not the transfer of the body.
+type responseHeaderTimeoutTransport struct {
+	http.RoundTripper
+	timeout func() time.Duration
+}
+
+func (t responseHeaderTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(t.timeout(), cancel)
+	resp, err := t.RoundTripper.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		// Timed out, the request context is canceled.
+		if err == nil {
+			resp.Body.Close()
+		}
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), errResponseHeaderTimeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody - releases the context of a request once its
+// response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // Load the json (typically from disk file).
 func jsonLoad(r io.ReadSeeker, data interface{}) error {
 	if _, err := r.Seek(0, io.SeekStart); err != nil {
@@ -744,6 +1502,71 @@ func ceilFrac(numerator, denominator int64) (ceil int64) {
 	return
 }
 
+// splitRange splits [0, total) into at most parts [start, end) ranges
+// whose sizes differ by at most one. No empty ranges are returned, so
+// fewer ranges are returned if parts is bigger than total.
+func splitRange(total, parts int64) [][2]int64 {
+	if total <= 0 || parts <= 0 {
+		return nil
+	}
+	if parts > total {
+		parts = total
+	}
+	ranges := make([][2]int64, 0, parts)
+	start := int64(0)
+	for i := int64(1); i <= parts; i++ {
+		end := ceilFrac(i*total, parts)
+		ranges = append(ranges, [2]int64{start, end})
+		start = end
+	}
+	return ranges
+}
+
+// retryMinBackoff is the smallest base backoff used by retryWithBackoff.
+const retryMinBackoff = time.Millisecond
+
+// retryWithBackoff calls fn until it succeeds or maxAttempts is reached,
+// sleeping with jittered exponential backoff (starting at base, capped at
+// max) between attempts. A base below retryMinBackoff is raised to it.
+// Errors wrapping errNonRetryable are returned immediately, a canceled
+// ctx stops the retries. The last error returned by fn is returned.
+func retryWithBackoff(ctx context.Context, maxAttempts int, base, max time.Duration, fn func() error) error {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if base < retryMinBackoff {
+		base = retryMinBackoff
+	}
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if errors.Is(err, errNonRetryable) || attempt == maxAttempts-1 {
+			return err
+		}
+
+		backoff := base << uint(attempt)
+		if backoff <= 0 || backoff > max {
+			// Cap the backoff, also guards against overflow.
+			backoff = max
+		}
+		// Sleep at least half the backoff, randomize the rest.
+		if half := int64(backoff / 2); half > 0 {
+			backoff = time.Duration(half + rand.Int63n(half+1))
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
 // pathClean is like path.Clean but does not return "." for
 // empty inputs, instead returns "empty" as is.
 func pathClean(p string) string {
@@ -912,6 +1735,26 @@ func getMinioMode() string {
 	return mode
 }
 
+// parseMinioMode - inverse of getMinioMode, returns the flags and gateway
+// name that produce mode. Distributed erasure implies erasure.
+func parseMinioMode(mode string) (isDist, isErasure, isGateway, isSD bool, gatewayName string, err error) {
+	switch mode {
+	case globalMinioModeFS:
+		return false, false, false, false, "", nil
+	case globalMinioModeErasureSD:
+		return false, false, false, true, "", nil
+	case globalMinioModeErasure:
+		return false, true, false, false, "", nil
+	case globalMinioModeDistErasure:
+		return true, true, false, false, "", nil
+	}
+	gatewayName = strings.TrimPrefix(mode, globalMinioModeGatewayPrefix)
+	if gatewayName == mode || gatewayName == "" {
+		return false, false, false, false, "", fmt.Errorf("unrecognized mode %q", mode)
+	}
+	return false, false, true, false, gatewayName, nil
+}
+
 func iamPolicyClaimNameOpenID() string {
 	return globalOpenIDConfig.GetIAMPolicyClaimName()
 }
@@ -1001,11 +1844,71 @@ func decodeDirObject(object string) string {
 	return object
 }
 
+const (
+	// slowDownBaseDelay - backoff of the first retry after a SlowDown.
+	slowDownBaseDelay = 100 * time.Millisecond
+	// slowDownMaxDelay - maximum backoff between retries after a
+	// SlowDown, unless a longer Retry-After is requested.
+	slowDownMaxDelay = 10 * time.Second
+)
+
+// slowDownBackoff - returns how long to wait before retrying a request
+// throttled with SlowDown or 503, attempt counts from 0. The Retry-After
+// header, in seconds or HTTP-date form, is honored if present, otherwise
+// the backoff grows exponentially up to slowDownMaxDelay with jitter.
+func slowDownBackoff(attempt int, retryAfterHeader string) time.Duration {
+	if retryAfterHeader = strings.TrimSpace(retryAfterHeader); retryAfterHeader != "" {
+		if secs, err := strconv.ParseInt(retryAfterHeader, 10, 64); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(retryAfterHeader); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	delay := slowDownMaxDelay
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt < 32 {
+		if d := slowDownBaseDelay << uint(attempt); d > 0 && d < slowDownMaxDelay {
+			delay = d
+		}
+	}
+	// Wait at least half the delay, the rest is random to spread
+	// retries of concurrent callers.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isNetworkError returns true if err is caused by the network, such as
+// internode RPC failures, timeouts, refused or reset connections and
+// truncated streams. Context cancellation and expired deadlines are not
+// network errors. The REST client counts network errors the same way.
+func isNetworkError(err error) bool {
+	return rest.IsNetworkError(err, true)
+}
+
 // This is used by metrics to show the number of failed RPC calls
 // between internodes
 func loadAndResetRPCNetworkErrsCounter() uint64 {
-	defer rest.ResetNetworkErrsCounter()
-	return rest.GetNetworkErrsCounter()
+	return rest.LoadAndResetNetworkErrsCounter()
+}
+
+// usableCapacity returns the approximate number of bytes available for
+// object data on an erasure set of drives, each with rawBytesPerDrive
+// bytes, using the default parity for the set size.
+func usableCapacity(rawBytesPerDrive int64, drives int) int64 {
+	if drives <= 0 || rawBytesPerDrive <= 0 {
+		return 0
+	}
+	if drives == 1 {
+		// Single drive setups do not use parity.
+		return rawBytesPerDrive
+	}
+	return rawBytesPerDrive * int64(drives-getDefaultParityBlocks(drives))
 }
 
 // Helper method to return total number of nodes in cluster
@@ -1018,6 +1921,122 @@ func totalNodeCount() uint64 {
 	return totalNodesCount
 }
 
+// peerProbeTimeout - how long reachableNodeCount waits for each peer.
+const peerProbeTimeout = 2 * time.Second
+
+// probePeer - returns nil if the liveness check of peer succeeds.
+var probePeer = func(ctx context.Context, peer string) error {
+	serverURL := &url.URL{
+		Scheme: getURLScheme(globalIsTLS),
+		Host:   peer,
+		Path:   pathJoin(healthCheckPathPrefix, healthCheckLivenessPath),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: globalTransports.Get(transportRoleInternode)}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer xhttp.DrainBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("peer %s is not live: %s", peer, resp.Status)
+	}
+	return nil
+}
+
+// reachableNodeCount - like totalNodeCount but only counts the peers
+// currently answering liveness checks, the local node is always counted.
+// Peers are probed in parallel with a short timeout.
+func reachableNodeCount(ctx context.Context) uint64 {
+	peers, local := globalEndpoints.peers()
+	return countReachablePeers(ctx, peers, local, probePeer)
+}
+
+func countReachablePeers(ctx context.Context, peers []string, local string, probe func(context.Context, string) error) uint64 {
+	if len(peers) == 0 {
+		return 1 // For standalone erasure coding
+	}
+
+	var (
+		wg        sync.WaitGroup
+		reachable uint64
+	)
+	for _, peer := range peers {
+		if peer == local {
+			atomic.AddUint64(&reachable, 1)
+			continue
+		}
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, peerProbeTimeout)
+			defer cancel()
+			if probe(ctx, peer) == nil {
+				atomic.AddUint64(&reachable, 1)
+			}
+		}(peer)
+	}
+	wg.Wait()
+	return reachable
+}
+
+// probeEndpoint - lightweight connectivity check of a configured target
+// endpoint, a TCP connect followed by a HEAD request for http(s)
+// endpoints. Any HTTP response counts as reachable, the whole probe is
+// bounded by timeout.
+func probeEndpoint(ctx context.Context, endpoint string, timeout time.Duration) error {
+	tr := newGatewayHTTPTransport(timeout)
+	defer tr.CloseIdleConnections()
+	return probeEndpointWithTransport(ctx, endpoint, timeout, tr)
+}
+
+// probeEndpointWithTransport - same as probeEndpoint, the HEAD request
+// is sent with tr.
+func probeEndpointWithTransport(ctx context.Context, endpoint string, timeout time.Duration, tr http.RoundTripper) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("endpoint %s has no host", endpoint)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", endpoint, err)
+	}
+	conn.Close()
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach %s: %w", endpoint, err)
+	}
+	xhttp.DrainBody(resp.Body)
+	return nil
+}
+
 // AuditLogOptions takes options for audit logging subsystem activity
 type AuditLogOptions struct {
 	Trigger   string
@@ -1059,7 +2078,7 @@ func newTLSConfig(getCert certs.GetCertificateFunc) *tls.Config {
 		MinVersion:               tls.VersionTLS12,
 		NextProtos:               []string{"http/1.1", "h2"},
 		GetCertificate:           getCert,
-		ClientSessionCache:       tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
+		ClientSessionCache:       newTLSSessionCache(),
 	}
 
 	tlsClientIdentity := env.Get(xtls.EnvIdentityTLSEnabled, "") == config.EnableOn
@@ -1067,7 +2086,7 @@ func newTLSConfig(getCert certs.GetCertificateFunc) *tls.Config {
 		tlsConfig.ClientAuth = tls.RequestClientCert
 	}
 
-	if secureCiphers := env.Get(api.EnvAPISecureCiphers, config.EnableOn) == config.EnableOn; secureCiphers {
+	if secureCiphersEnabled() {
 		tlsConfig.CipherSuites = fips.TLSCiphers()
 	} else {
 		tlsConfig.CipherSuites = fips.TLSCiphersBackwardCompatible()
@@ -1076,6 +2095,50 @@ func newTLSConfig(getCert certs.GetCertificateFunc) *tls.Config {
 	return tlsConfig
 }
 
+// secureCiphersEnabled returns true unless backward compatible cipher
+// suites were enabled with MINIO_API_SECURE_CIPHERS=off.
+func secureCiphersEnabled() bool {
+	return env.Get(api.EnvAPISecureCiphers, config.EnableOn) == config.EnableOn
+}
+
+// TLSReport - TLS settings of the server.
+type TLSReport struct {
+	FIPS          bool     `json:"fips"`
+	SecureCiphers bool     `json:"secureCiphers"`
+	MinVersion    string   `json:"minVersion"`
+	CipherSuites  []string `json:"cipherSuites"`
+	Curves        []string `json:"curves"`
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// TLSSecurityReport returns the TLS settings the server uses, as
+// configured by newTLSConfig.
+func TLSSecurityReport() TLSReport {
+	tlsConfig := newTLSConfig(func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return nil, errors.New("not implemented")
+	})
+	report := TLSReport{
+		FIPS:          fips.Enabled,
+		SecureCiphers: secureCiphersEnabled(),
+		MinVersion:    tlsVersionNames[tlsConfig.MinVersion],
+		CipherSuites:  make([]string, 0, len(tlsConfig.CipherSuites)),
+		Curves:        make([]string, 0, len(tlsConfig.CurvePreferences)),
+	}
+	for _, id := range tlsConfig.CipherSuites {
+		report.CipherSuites = append(report.CipherSuites, tls.CipherSuiteName(id))
+	}
+	for _, curve := range tlsConfig.CurvePreferences {
+		report.Curves = append(report.Curves, curve.String())
+	}
+	return report
+}
+
 /////////// Types and functions for OpenID IAM testing
 
 // OpenIDClientAppParams - contains openID client application params, used in
@@ -1214,3 +2277,60 @@ func MockOpenIDTestUserInteraction(ctx context.Context, pro OpenIDClientAppParam
 	// fmt.Printf("TOKEN: %s\n", rawIDToken)
 	return rawIDToken, nil
 }
+
+// cloudMetadataIPs - instance metadata services of the major cloud
+// providers which are not covered by the link-local ranges.
+var cloudMetadataIPs = []net.IP{
+	net.ParseIP("100.100.100.200"), // Alibaba Cloud
+	net.ParseIP("fd00:ec2::254"),   // AWS IPv6
+}
+
+// lookupIP - resolves the addresses of a host, replaceable in tests.
+var lookupIP = net.LookupIP
+
+// isInternalEndpoint - returns true if host, with or without a port,
+// is or resolves to a loopback, link-local, unspecified or cloud
+// metadata address. Configured target endpoints pointing at such
+// addresses can be abused for server side request forgery.
+func isInternalEndpoint(host string) bool {
+	return checkInternalEndpoint(host, false)
+}
+
+// isInternalEndpointStrict - same as isInternalEndpoint but also
+// treats the private RFC 1918 and RFC 4193 ranges as internal.
+func isInternalEndpointStrict(host string) bool {
+	return checkInternalEndpoint(host, true)
+}
+
+func checkInternalEndpoint(host string, strict bool) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" {
+		return false
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		if ips, err = lookupIP(host); err != nil {
+			// Unresolvable hosts cannot be connected to either.
+			return false
+		}
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+			return true
+		}
+		for _, metadataIP := range cloudMetadataIPs {
+			if ip.Equal(metadataIP) {
+				return true
+			}
+		}
+		if strict && ip.IsPrivate() {
+			return true
+		}
+	}
+	return false
+}
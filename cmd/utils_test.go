@@ -18,18 +18,40 @@
 package cmd
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/madmin-go"
+	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/config/api"
+	"github.com/minio/minio/internal/config/compress"
+	"github.com/minio/minio/internal/fips"
+	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/rest"
 )
 
 // Tests maximum object size.
@@ -55,6 +77,106 @@ func TestMaxObjectSize(t *testing.T) {
 			t.Errorf("Test %d: Expected %t, got %t", i+1, s.isMax, isMax)
 		}
 	}
+
+	// Override the maximum object size via the api config.
+	globalAPIConfig.mu.Lock()
+	globalAPIConfig.maxObjectSize = 100 * humanize.GiByte
+	globalAPIConfig.mu.Unlock()
+	defer func() {
+		globalAPIConfig.mu.Lock()
+		globalAPIConfig.maxObjectSize = 0
+		globalAPIConfig.mu.Unlock()
+	}()
+
+	if !isMaxObjectSize(100*humanize.GiByte + 1) {
+		t.Error("Expected object larger than configured max object size to be rejected")
+	}
+	if isMaxObjectSize(100 * humanize.GiByte) {
+		t.Error("Expected object equal to configured max object size to be allowed")
+	}
+}
+
+// Tests checking object size and part count limits together.
+func TestCheckObjectSizeLimit(t *testing.T) {
+	testCases := []struct {
+		size      int64
+		maxPartID int
+		err       error
+	}{
+		// Unknown size, single PUT.
+		{-1, 0, nil},
+		// Exactly at the maximum object size.
+		{globalMaxObjectSize, 0, nil},
+		// Just beyond the maximum object size.
+		{globalMaxObjectSize + 1, 0, errDataTooLarge},
+		// Beyond maximum object size with valid part count.
+		{globalMaxObjectSize + 1, globalMaxPartID, errDataTooLarge},
+		// Unknown size with maximum number of parts.
+		{-1, globalMaxPartID, nil},
+		// Unknown size with one part too many.
+		{-1, globalMaxPartID + 1, errTooManyParts},
+		// Maximum object size split across maximum number of parts.
+		{globalMaxObjectSize, globalMaxPartID, nil},
+		// Size too small to be split into the requested parts.
+		{globalMinPartSize*(globalMaxPartID-1) - 1, globalMaxPartID, errTooManyParts},
+		// Smallest size that can be split into the maximum number of parts.
+		{globalMinPartSize * (globalMaxPartID - 1), globalMaxPartID, nil},
+	}
+	for i, testCase := range testCases {
+		if err := checkObjectSizeLimit(testCase.size, testCase.maxPartID); err != testCase.err {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.err, err)
+		}
+	}
+}
+
+// Tests quorum computation for storage class ratios.
+func TestQuorumForStorageClass(t *testing.T) {
+	testCases := []struct {
+		drives, data, parity int
+		readQ, writeQ        int
+		success              bool
+	}{
+		// EC:2 on 4 drives, data equals parity.
+		{4, 2, 2, 2, 3, true},
+		// EC:2 on 8 drives.
+		{8, 6, 2, 6, 6, true},
+		// EC:4 on 8 drives, data equals parity.
+		{8, 4, 4, 4, 5, true},
+		// EC:4 on 16 drives.
+		{16, 12, 4, 12, 12, true},
+		// Blocks do not add up to the number of drives.
+		{8, 4, 2, 0, 0, false},
+		{4, 4, 2, 0, 0, false},
+		// Parity more than half the drives.
+		{8, 2, 6, 0, 0, false},
+		// Invalid input.
+		{0, 0, 0, 0, 0, false},
+	}
+	for i, testCase := range testCases {
+		readQ, writeQ, err := quorumForStorageClass(testCase.drives, testCase.data, testCase.parity)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected to pass, got %v", i+1, err)
+			continue
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+			continue
+		}
+		if readQ != testCase.readQ || writeQ != testCase.writeQ {
+			t.Errorf("Test %d: Expected quorum %d/%d, got %d/%d", i+1, testCase.readQ, testCase.writeQ, readQ, writeQ)
+		}
+	}
+	// Must agree with getWriteQuorum for the default parity.
+	for _, drives := range []int{4, 8, 16} {
+		parity := getDefaultParityBlocks(drives)
+		_, writeQ, err := quorumForStorageClass(drives, drives-parity, parity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if writeQ != getWriteQuorum(drives) {
+			t.Errorf("Expected write quorum %d for %d drives, got %d", getWriteQuorum(drives), drives, writeQ)
+		}
+	}
 }
 
 // Tests minimum allowed part size.
@@ -291,38 +413,73 @@ func TestToS3ETag(t *testing.T) {
 	}
 }
 
-// Test contains
-func TestContains(t *testing.T) {
-	testErr := errors.New("test err")
+// Test ETagWriter
+func TestETagWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefghijklmnopqrstuvwxyz"), 1000)
+	expected := ToS3ETag(getMD5Hash(data))
 
+	var w ETagWriter
+	for _, chunkSize := range []int{1, 7, 4096, 10000} {
+		w = ETagWriter{}
+		for off := 0; off < len(data); off += chunkSize {
+			end := off + chunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			if _, err := w.Write(data[off:end]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if etag := w.ETag(); etag != expected {
+			t.Fatalf("chunk size %d: expected %s, got %s", chunkSize, expected, etag)
+		}
+	}
+
+	w = ETagWriter{}
+	if etag, expected := w.ETag(), ToS3ETag(getMD5Hash(nil)); etag != expected {
+		t.Fatalf("empty: expected %s, got %s", expected, etag)
+	}
+}
+
+// Test containsString
+func TestContainsString(t *testing.T) {
 	testCases := []struct {
-		slice interface{}
-		elem  interface{}
+		slice []string
+		elem  string
 		found bool
 	}{
-		{nil, nil, false},
-		{"1", "1", false},
+		{nil, "", false},
 		{nil, "1", false},
-		{[]string{"1"}, nil, false},
 		{[]string{}, "1", false},
 		{[]string{"1"}, "1", true},
 		{[]string{"2"}, "1", false},
 		{[]string{"1", "2"}, "1", true},
 		{[]string{"2", "1"}, "1", true},
 		{[]string{"2", "1", "3"}, "1", true},
-		{[]int{1, 2, 3}, "1", false},
-		{[]int{1, 2, 3}, 2, true},
-		{[]int{1, 2, 3, 4, 5, 6}, 7, false},
-		{[]error{errors.New("new err")}, testErr, false},
-		{[]error{errors.New("new err"), testErr}, testErr, true},
+		{[]string{""}, "", true},
 	}
 
 	for i, testCase := range testCases {
-		found := contains(testCase.slice, testCase.elem)
+		found := containsString(testCase.slice, testCase.elem)
 		if found != testCase.found {
 			t.Fatalf("Test %v: expected: %v, got: %v", i+1, testCase.found, found)
 		}
 	}
+
+	headers := []string{"content-md5", "content-type", "x-amz-content-sha256", "x-amz-date", "host"}
+	if n := testing.AllocsPerRun(100, func() {
+		containsString(headers, "host")
+	}); n != 0 {
+		t.Fatalf("Expected no allocations, got %v", n)
+	}
+}
+
+func BenchmarkContainsString(b *testing.B) {
+	headers := []string{"content-md5", "content-type", "x-amz-content-sha256", "x-amz-date", "host"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		containsString(headers, "host")
+	}
 }
 
 // Test jsonLoad.
@@ -395,6 +552,53 @@ func TestCeilFrac(t *testing.T) {
 	}
 }
 
+// Test usableCapacity
+func TestUsableCapacity(t *testing.T) {
+	const driveSize = 1 * humanize.TiByte
+	cases := []struct {
+		drives int
+		usable int64
+	}{
+		{4, 2 * driveSize},
+		{8, 4 * driveSize},
+		{16, 12 * driveSize},
+		{1, driveSize},
+		{0, 0},
+		{-1, 0},
+	}
+	for i, testCase := range cases {
+		if usable := usableCapacity(driveSize, testCase.drives); usable != testCase.usable {
+			t.Errorf("Case %d: expected %d, got %d", i, testCase.usable, usable)
+		}
+	}
+}
+
+// Test splitRange
+func TestSplitRange(t *testing.T) {
+	cases := []struct {
+		total, parts int64
+		ranges       [][2]int64
+	}{
+		// Exact division.
+		{12, 3, [][2]int64{{0, 4}, {4, 8}, {8, 12}}},
+		// Remainder.
+		{10, 3, [][2]int64{{0, 4}, {4, 7}, {7, 10}}},
+		{10, 4, [][2]int64{{0, 3}, {3, 5}, {5, 8}, {8, 10}}},
+		// More parts than items.
+		{2, 5, [][2]int64{{0, 1}, {1, 2}}},
+		{1, 1, [][2]int64{{0, 1}}},
+		// Nothing to split.
+		{0, 3, nil},
+		{10, 0, nil},
+	}
+	for i, testCase := range cases {
+		ranges := splitRange(testCase.total, testCase.parts)
+		if !reflect.DeepEqual(ranges, testCase.ranges) {
+			t.Errorf("Case %d: expected %v, got %v", i, testCase.ranges, ranges)
+		}
+	}
+}
+
 // Test if isErrIgnored works correctly.
 func TestIsErrIgnored(t *testing.T) {
 	errIgnored := fmt.Errorf("ignored error")
@@ -514,3 +718,1185 @@ func TestTimedValue(t *testing.T) {
 		t.Fatalf("expected time to be un-equal: %s == %s", t1, t3)
 	}
 }
+
+// Test retryWithBackoff
+func TestRetryWithBackoff(t *testing.T) {
+	errTransient := errors.New("transient error")
+
+	// Success on first attempt.
+	var calls int
+	err := retryWithBackoff(context.Background(), 5, time.Millisecond, 10*time.Millisecond, func() error {
+		calls++
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Fatalf("expected success on first attempt, got err: %v after %d calls", err, calls)
+	}
+
+	// Success after retries.
+	calls = 0
+	err = retryWithBackoff(context.Background(), 5, time.Millisecond, 10*time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success after 3 attempts, got err: %v after %d calls", err, calls)
+	}
+
+	// All attempts fail, last error is returned.
+	calls = 0
+	err = retryWithBackoff(context.Background(), 3, time.Millisecond, 10*time.Millisecond, func() error {
+		calls++
+		return fmt.Errorf("attempt %d: %w", calls, errTransient)
+	})
+	if !errors.Is(err, errTransient) || err.Error() != "attempt 3: transient error" || calls != 3 {
+		t.Fatalf("expected last error after 3 attempts, got err: %v after %d calls", err, calls)
+	}
+
+	// Context canceled mid-retry.
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	err = retryWithBackoff(ctx, 10, time.Hour, time.Hour, func() error {
+		calls++
+		cancel()
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 1 {
+		t.Fatalf("expected retries to stop on cancel, got err: %v after %d calls", err, calls)
+	}
+	if time.Since(start) > time.Minute {
+		t.Fatal("expected retries to stop without waiting for backoff")
+	}
+
+	// Non-retryable errors short-circuit.
+	calls = 0
+	err = retryWithBackoff(context.Background(), 5, time.Millisecond, 10*time.Millisecond, func() error {
+		calls++
+		return fmt.Errorf("bad request: %w", errNonRetryable)
+	})
+	if !errors.Is(err, errNonRetryable) || calls != 1 {
+		t.Fatalf("expected non-retryable error to stop retries, got err: %v after %d calls", err, calls)
+	}
+
+	// A zero base is clamped instead of waiting for max on every attempt.
+	calls = 0
+	start = time.Now()
+	err = retryWithBackoff(context.Background(), 3, 0, time.Hour, func() error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 3 {
+		t.Fatalf("expected 3 attempts with zero base, got err: %v after %d calls", err, calls)
+	}
+	if time.Since(start) > time.Minute {
+		t.Fatal("expected zero base to be clamped to the minimum backoff")
+	}
+}
+
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "i/o timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
+
+func TestIsNetworkError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil, expected: false},
+		// Timeout.
+		{err: &net.OpError{Op: "read", Net: "tcp", Err: testTimeoutError{}}, expected: true},
+		{err: fmt.Errorf("read: %w", testTimeoutError{}), expected: true},
+		// Connection reset and refused.
+		{err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, expected: true},
+		{err: fmt.Errorf("dial: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), expected: true},
+		{err: syscall.ECONNRESET, expected: true},
+		{err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), expected: true},
+		// Truncated stream.
+		{err: io.ErrUnexpectedEOF, expected: true},
+		{err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), expected: true},
+		// Internode RPC errors.
+		{err: &rest.NetworkError{Err: &url.Error{Op: "Post", URL: "http://node1", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}}, expected: true},
+		// Context cancellation.
+		{err: context.Canceled, expected: false},
+		{err: fmt.Errorf("request: %w", context.DeadlineExceeded), expected: false},
+		// Business errors.
+		{err: errFileNotFound, expected: false},
+		{err: BucketNotFound{Bucket: "bucket"}, expected: false},
+	}
+	for i, testCase := range testCases {
+		if got := isNetworkError(testCase.err); got != testCase.expected {
+			t.Errorf("Test %d: expected %t for %v, got %t", i+1, testCase.expected, testCase.err, got)
+		}
+	}
+}
+
+func TestParseMinioMode(t *testing.T) {
+	defer func(isDist, isErasure, isGateway, isSD bool, gatewayName string) {
+		globalIsDistErasure, globalIsErasure, globalIsGateway, globalIsErasureSD = isDist, isErasure, isGateway, isSD
+		globalGatewayName = gatewayName
+	}(globalIsDistErasure, globalIsErasure, globalIsGateway, globalIsErasureSD, globalGatewayName)
+
+	testCases := []struct {
+		mode                               string
+		isDist, isErasure, isGateway, isSD bool
+		gatewayName                        string
+	}{
+		{mode: globalMinioModeFS},
+		{mode: globalMinioModeErasureSD, isSD: true},
+		{mode: globalMinioModeErasure, isErasure: true},
+		{mode: globalMinioModeDistErasure, isDist: true, isErasure: true},
+		{mode: globalMinioModeGatewayPrefix + "nas", isGateway: true, gatewayName: "nas"},
+		{mode: globalMinioModeGatewayPrefix + "azure", isGateway: true, gatewayName: "azure"},
+	}
+	for _, testCase := range testCases {
+		globalIsDistErasure, globalIsErasure, globalIsGateway, globalIsErasureSD = testCase.isDist, testCase.isErasure, testCase.isGateway, testCase.isSD
+		globalGatewayName = testCase.gatewayName
+		mode := getMinioMode()
+		if mode != testCase.mode {
+			t.Fatalf("Expected mode %s, got %s", testCase.mode, mode)
+		}
+		isDist, isErasure, isGateway, isSD, gatewayName, err := parseMinioMode(mode)
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if isDist != testCase.isDist || isErasure != testCase.isErasure || isGateway != testCase.isGateway ||
+			isSD != testCase.isSD || gatewayName != testCase.gatewayName {
+			t.Fatalf("%s: unexpected (%t, %t, %t, %t, %s)", mode, isDist, isErasure, isGateway, isSD, gatewayName)
+		}
+	}
+
+	for _, mode := range []string{"", "mode-server", globalMinioModeGatewayPrefix, "mode-server-xl-multi"} {
+		if _, _, _, _, _, err := parseMinioMode(mode); err == nil {
+			t.Fatalf("%q: expected an error", mode)
+		}
+	}
+}
+
+func TestSetInternodeRequestID(t *testing.T) {
+	const requestID = "16FC9DC0B1C37E8E"
+
+	var got string
+	h := setInternodeRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = logger.GetReqInfo(newContext(r, w, "ServerInfo")).RequestID
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://node2:9000/minio/peer/v1/serverinfo", nil)
+	req.Header.Set(xhttp.AmzRequestID, requestID)
+	w := httptest.NewRecorder()
+	w.Header().Set(xhttp.AmzRequestID, "locally-generated")
+	h.ServeHTTP(w, req)
+	if got != requestID {
+		t.Fatalf("Expected request ID %s, got %s", requestID, got)
+	}
+
+	// The locally generated request ID is kept without a request ID.
+	req = httptest.NewRequest(http.MethodPost, "http://node2:9000/minio/peer/v1/serverinfo", nil)
+	w = httptest.NewRecorder()
+	w.Header().Set(xhttp.AmzRequestID, "locally-generated")
+	h.ServeHTTP(w, req)
+	if got != "locally-generated" {
+		t.Fatalf("Expected the local request ID, got %s", got)
+	}
+}
+
+func TestTLSSecurityReport(t *testing.T) {
+	for _, secureCiphers := range []bool{true, false} {
+		expectedCiphers := fips.TLSCiphers()
+		if secureCiphers {
+			t.Setenv(api.EnvAPISecureCiphers, config.EnableOn)
+		} else {
+			t.Setenv(api.EnvAPISecureCiphers, config.EnableOff)
+			expectedCiphers = fips.TLSCiphersBackwardCompatible()
+		}
+
+		report := TLSSecurityReport()
+		if report.FIPS != fips.Enabled {
+			t.Fatalf("Expected FIPS %t, got %t", fips.Enabled, report.FIPS)
+		}
+		if report.SecureCiphers != secureCiphers {
+			t.Fatalf("Expected secure ciphers %t, got %t", secureCiphers, report.SecureCiphers)
+		}
+		if report.MinVersion != "TLS 1.2" {
+			t.Fatalf("Expected min version TLS 1.2, got %s", report.MinVersion)
+		}
+		if len(report.CipherSuites) != len(expectedCiphers) {
+			t.Fatalf("Expected %d cipher suites, got %v", len(expectedCiphers), report.CipherSuites)
+		}
+		for i, id := range expectedCiphers {
+			if report.CipherSuites[i] != tls.CipherSuiteName(id) {
+				t.Fatalf("Expected cipher suite %s, got %s", tls.CipherSuiteName(id), report.CipherSuites[i])
+			}
+		}
+		curves := fips.TLSCurveIDs()
+		if len(report.Curves) != len(curves) {
+			t.Fatalf("Expected %d curves, got %v", len(curves), report.Curves)
+		}
+		for i, curve := range curves {
+			if report.Curves[i] != curve.String() {
+				t.Fatalf("Expected curve %s, got %s", curve, report.Curves[i])
+			}
+		}
+	}
+}
+
+func TestNewClientTLSConfig(t *testing.T) {
+	tlsConfig := newClientTLSConfig(clientTLSOpts{ServerName: "minio.example.com"})
+	if tlsConfig.RootCAs != globalRootCAs {
+		t.Fatal("Expected global root CAs to be used")
+	}
+	if tlsConfig.ServerName != "minio.example.com" {
+		t.Fatalf("Expected SNI override, got %s", tlsConfig.ServerName)
+	}
+	if tlsConfig.ClientSessionCache == nil {
+		t.Fatal("Expected a client session cache")
+	}
+	if tlsConfig.InsecureSkipVerify {
+		t.Fatal("Expected certificate verification by default")
+	}
+
+	// Skipping verification is refused unless explicitly allowed.
+	t.Setenv(config.EnvTLSAllowInsecureSkipVerify, "")
+	if tlsConfig = newClientTLSConfig(clientTLSOpts{InsecureSkipVerify: true}); tlsConfig.InsecureSkipVerify {
+		t.Fatal("Expected skipping certificate verification to be refused")
+	}
+	t.Setenv(config.EnvTLSAllowInsecureSkipVerify, config.EnableOn)
+	if tlsConfig = newClientTLSConfig(clientTLSOpts{InsecureSkipVerify: true}); !tlsConfig.InsecureSkipVerify {
+		t.Fatal("Expected skipping certificate verification to be allowed")
+	}
+}
+
+func TestProxyAllowlistTransport(t *testing.T) {
+	globalAPIConfig.mu.Lock()
+	savedHosts := globalAPIConfig.proxyAllowedHosts
+	globalAPIConfig.mu.Unlock()
+	defer func() {
+		globalAPIConfig.mu.Lock()
+		globalAPIConfig.proxyAllowedHosts = savedHosts
+		globalAPIConfig.mu.Unlock()
+	}()
+
+	// Stands in for an HTTP proxy, answers any request.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	defer tr.CloseIdleConnections()
+	rt := proxyAllowlistTransport{tr}
+
+	testCases := []struct {
+		allowedHosts []string
+		url          string
+		allowed      bool
+	}{
+		// Empty allowlist, any host.
+		{allowedHosts: nil, url: "http://anywhere.example.com/", allowed: true},
+		// Allowed by host and by host:port.
+		{allowedHosts: []string{"minio.example.com"}, url: "http://minio.example.com:9000/", allowed: true},
+		{allowedHosts: []string{"minio.example.com:9000"}, url: "http://minio.example.com:9000/", allowed: true},
+		// Blocked, allowing the proxy does not allow its destinations.
+		{allowedHosts: []string{"minio.example.com", proxyURL.Host}, url: "http://169.254.169.254/", allowed: false},
+		{allowedHosts: []string{"minio.example.com:9000"}, url: "http://minio.example.com:9001/", allowed: false},
+	}
+	for i, testCase := range testCases {
+		globalAPIConfig.mu.Lock()
+		globalAPIConfig.proxyAllowedHosts = testCase.allowedHosts
+		globalAPIConfig.mu.Unlock()
+
+		req, err := http.NewRequest(http.MethodGet, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if testCase.allowed {
+			if err != nil {
+				t.Fatalf("Test %d: expected %s to be proxied, got %v", i+1, testCase.url, err)
+			}
+			resp.Body.Close()
+			continue
+		}
+		if !errors.Is(err, errProxyHostNotAllowed) {
+			t.Fatalf("Test %d: expected %s to be refused, got %v", i+1, testCase.url, err)
+		}
+	}
+}
+
+// The gateway transport waits up to its ResponseHeaderTimeout, but
+// no longer than the deadline of the request context.
+func TestGatewayTransportRequestDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+
+	tr := newGatewayHTTPTransport(time.Minute)
+	defer tr.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, slow.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err = tr.RoundTrip(req); err == nil {
+		t.Fatal("expected request with short deadline to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request took %v, expected it to be bounded by the context deadline", elapsed)
+	}
+
+	req, err = http.NewRequest(http.MethodGet, fast.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestGenChecksumETag(t *testing.T) {
+	etag, err := GenChecksumETag("md5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(etag) != len(GenETag()) || !strings.HasSuffix(etag, "-1") {
+		t.Errorf("md5: unexpected ETag %q", etag)
+	}
+
+	etag, err = GenChecksumETag("sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := base64.StdEncoding.DecodeString(etag)
+	if err != nil {
+		t.Fatalf("sha256: ETag %q is not base64: %v", etag, err)
+	}
+	if len(sum) != 32 {
+		t.Errorf("sha256: expected 32 byte digest, got %d", len(sum))
+	}
+
+	if _, err = GenChecksumETag("crc32"); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+}
+
+func TestValidatePart(t *testing.T) {
+	testCases := []struct {
+		partID int
+		size   int64
+		isLast bool
+		err    error
+	}{
+		{1, globalMinPartSize, false, nil},
+		{1, globalMinPartSize - 1, false, PartTooSmall{PartSize: globalMinPartSize - 1, PartNumber: 1}},
+		{2, 1, true, nil},
+		{3, globalMaxPartSize + 1, true, PartTooBig{}},
+		{0, globalMinPartSize, false, errInvalidPartID},
+		{globalMaxPartID + 1, globalMinPartSize, true, errInvalidPartID},
+	}
+	for i, testCase := range testCases {
+		if err := validatePart(testCase.partID, testCase.size, testCase.isLast); err != testCase.err {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.err, err)
+		}
+	}
+}
+
+func TestIsCompressibleContentType(t *testing.T) {
+	globalCompressConfigMu.Lock()
+	prevCfg := globalCompressConfig
+	globalCompressConfig = compress.Config{
+		Enabled:    true,
+		Extensions: strings.Split(compress.DefaultExtensions, config.ValueSeparator),
+		MimeTypes:  strings.Split(compress.DefaultMimeTypes, config.ValueSeparator),
+	}
+	globalCompressConfigMu.Unlock()
+	defer func() {
+		globalCompressConfigMu.Lock()
+		globalCompressConfig = prevCfg
+		globalCompressConfigMu.Unlock()
+	}()
+
+	testCases := []struct {
+		contentType string
+		ext         string
+		expected    bool
+	}{
+		{"application/json", "", true},
+		{"text/csv", ".csv", true},
+		{"Application/JSON", "", true},
+		{"IMAGE/JPEG", "", false},
+		{"", "object.log", true},
+		{"image/jpeg", "", false},
+		{"application/octet-stream", "", false},
+		{"application/gzip", ".json", false},
+		{"application/json", ".zip", false},
+	}
+	for i, testCase := range testCases {
+		if got := isCompressibleContentType(testCase.contentType, testCase.ext); got != testCase.expected {
+			t.Errorf("Test %d: expected %t for %q %q, got %t", i+1, testCase.expected, testCase.contentType, testCase.ext, got)
+		}
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	const policy = `{"Version":"2012-10-17","Statement":[]}`
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket?policy", strings.NewReader(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err = json.NewDecoder(limitBody(req, int64(len(policy)))).Decode(&v); err != nil {
+		t.Fatalf("Expected body within limit to be decoded, got %v", err)
+	}
+
+	req, err = http.NewRequest(http.MethodPut, "http://localhost:9000/bucket?policy", strings.NewReader(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.NewDecoder(limitBody(req, 10)).Decode(&v)
+	if !errors.Is(err, errRequestBodyTooLarge) {
+		t.Fatalf("Expected %v, got %v", errRequestBodyTooLarge, err)
+	}
+}
+
+func TestSetContentMD5(t *testing.T) {
+	body := []byte("The quick brown fox jumps over the lazy dog")
+	// base64 of the MD5 9e107d9d372bb6826bd81d3542a419d6
+	const expected = "nhB9nTcrtoJr2B01QqQZ1g=="
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setContentMD5(req, body)
+	if got := req.Header.Get("Content-Md5"); got != expected {
+		t.Fatalf("Expected Content-MD5 %s, got %s", expected, got)
+	}
+
+	req, err = http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = setContentMD5Reader(req, bytes.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Content-Md5"); got != expected {
+		t.Fatalf("Expected Content-MD5 %s, got %s", expected, got)
+	}
+	if req.ContentLength != int64(len(body)) {
+		t.Fatalf("Expected content length %d, got %d", len(body), req.ContentLength)
+	}
+	sent, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sent, body) {
+		t.Fatalf("Expected the full body to be sent, got %q", sent)
+	}
+}
+
+func TestGetProfileDataFolded(t *testing.T) {
+	globalProfilerMu.Lock()
+	prevProfiler := globalProfiler
+	globalProfilerMu.Unlock()
+	defer func() {
+		globalProfilerMu.Lock()
+		globalProfiler = prevProfiler
+		globalProfilerMu.Unlock()
+	}()
+
+	line := regexp.MustCompile(`^[^ ;]+(;[^ ;]+)* [0-9]+$`)
+	// The runtime allows a single active CPU profile, capture them one after another.
+	for _, profilerType := range []string{string(madmin.ProfilerCPU), string(madmin.ProfilerCPU) + ":one"} {
+		prof, err := startProfiler(profilerType)
+		if err != nil {
+			t.Skip("CPU profiler not available:", err)
+		}
+		globalProfilerMu.Lock()
+		globalProfiler = map[string]minioProfiler{profilerType: prof}
+		globalProfilerMu.Unlock()
+
+		// Keep the CPU busy long enough to collect samples.
+		var sum uint64
+		for deadline := time.Now().Add(500 * time.Millisecond); time.Now().Before(deadline); {
+			for i := 0; i < 100000; i++ {
+				sum += uint64(i) ^ sum
+			}
+		}
+		_ = sum
+
+		data, err := getProfileData(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := profileDataName(profilerType)
+		if len(data[name+".pprof"]) == 0 {
+			t.Fatalf("Expected raw CPU profile %s.pprof", name)
+		}
+		folded := strings.TrimSuffix(string(data[name+".folded"]), "\n")
+		if folded == "" {
+			t.Fatalf("Expected folded CPU profile %s.folded", name)
+		}
+		for _, l := range strings.Split(folded, "\n") {
+			if !line.MatchString(l) {
+				t.Fatalf("Malformed folded line %q", l)
+			}
+		}
+	}
+}
+
+func TestGetProfileDataLabeledTrace(t *testing.T) {
+	globalProfilerMu.Lock()
+	prevProfiler := globalProfiler
+	globalProfilerMu.Unlock()
+	defer func() {
+		globalProfilerMu.Lock()
+		globalProfiler = prevProfiler
+		globalProfilerMu.Unlock()
+	}()
+
+	// The runtime allows a single active trace, capture them one after another.
+	for _, label := range []string{"one", "two"} {
+		profilerType := string(madmin.ProfilerTrace) + ":" + label
+		prof, err := startProfiler(profilerType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		globalProfilerMu.Lock()
+		globalProfiler = map[string]minioProfiler{profilerType: prof}
+		globalProfilerMu.Unlock()
+
+		data, err := getProfileData(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := data["trace-"+label+".trace"]; !ok || len(data) != 1 {
+			t.Fatalf("Expected a single trace-%s.trace, got %v", label, len(data))
+		}
+	}
+
+	for _, profilerType := range []string{"trace:", "trace:a/b", "cpu:one"} {
+		if _, err := startProfiler(profilerType); err == nil {
+			t.Errorf("Expected an error for profiler type %q", profilerType)
+		}
+	}
+}
+
+func TestProfileBundle(t *testing.T) {
+	prof, err := startProfiler(string(madmin.ProfilerGoroutines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalProfilerMu.Lock()
+	prevProfiler := globalProfiler
+	globalProfiler = map[string]minioProfiler{string(madmin.ProfilerGoroutines): prof}
+	globalProfilerMu.Unlock()
+	defer func() {
+		globalProfilerMu.Lock()
+		globalProfiler = prevProfiler
+		globalProfilerMu.Unlock()
+	}()
+
+	bundle, err := ProfileBundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mf, ok := files[profileBundleManifestName]
+	if !ok {
+		t.Fatalf("Expected %s in the bundle", profileBundleManifestName)
+	}
+	rc, err := mf.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	var manifest profileBundleManifest
+	if err = json.NewDecoder(rc).Decode(&manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.GoVersion != runtime.Version() || manifest.Mode != getMinioMode() || manifest.Time.IsZero() {
+		t.Fatalf("Unexpected manifest %+v", manifest)
+	}
+	if len(manifest.Profiles) == 0 {
+		t.Fatal("Expected at least one profile in the manifest")
+	}
+	for _, name := range manifest.Profiles {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected profile %s in the bundle", name)
+		}
+	}
+	if _, ok := files["goroutines.txt"]; !ok {
+		t.Errorf("Expected goroutines.txt in the bundle, got %v", manifest.Profiles)
+	}
+
+	if _, err = ProfileBundle(); err == nil {
+		t.Fatal("Expected an error without active profiles")
+	}
+}
+
+func TestProfilerRatesRestored(t *testing.T) {
+	prevRates := snapshotProfilerRates()
+	defer restoreProfilerRates(prevRates)
+
+	restoreProfilerRates(profilerRates{block: 10, mutexFraction: 5})
+
+	prof, err := startProfiler(string(madmin.ProfilerBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates := snapshotProfilerRates(); rates.block != 100 {
+		t.Fatalf("Expected block profile rate 100 while profiling, got %d", rates.block)
+	}
+	if _, err = prof.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if rates := snapshotProfilerRates(); rates != (profilerRates{block: 10, mutexFraction: 5}) {
+		t.Fatalf("Expected the previous rates to be restored, got %+v", rates)
+	}
+
+	prof, err = startProfiler(string(madmin.ProfilerMutex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prof.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if rates := snapshotProfilerRates(); rates != (profilerRates{block: 10, mutexFraction: 5}) {
+		t.Fatalf("Expected the previous rates to be restored, got %+v", rates)
+	}
+}
+
+func TestInstallProfilerExpiry(t *testing.T) {
+	prevDuration := globalProfilerMaxDuration
+	globalProfilerMaxDuration = 50 * time.Millisecond
+	globalProfilerMu.Lock()
+	prevProfiler := globalProfiler
+	globalProfiler = make(map[string]minioProfiler)
+	globalProfilerMu.Unlock()
+	defer func() {
+		globalProfilerMu.Lock()
+		globalProfiler = prevProfiler
+		globalProfilerMu.Unlock()
+		globalProfilerMaxDuration = prevDuration
+	}()
+
+	prof, err := startProfiler(string(madmin.ProfilerBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalProfilerMu.Lock()
+	installProfiler(string(madmin.ProfilerBlock), prof)
+	globalProfilerMu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		globalProfilerMu.Lock()
+		n := len(globalProfiler)
+		globalProfilerMu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected profiler to expire")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err = getProfileData(false); err == nil {
+		t.Fatal("Expected no active profile after expiry")
+	}
+}
+
+func TestStartAndInstallProfiler(t *testing.T) {
+	globalProfilerMu.Lock()
+	defer globalProfilerMu.Unlock()
+
+	prevProfiler := globalProfiler
+	globalProfiler = nil
+	defer func() {
+		for _, p := range globalProfiler {
+			p.Stop()
+		}
+		globalProfiler = prevProfiler
+	}()
+
+	profiler := string(madmin.ProfilerMutex)
+	if err := startAndInstallProfiler(profiler); err != nil {
+		t.Fatal(err)
+	}
+	if err := startAndInstallProfiler(profiler); err != errProfilerAlreadyActive {
+		t.Fatalf("Expected %v, got %v", errProfilerAlreadyActive, err)
+	}
+	if len(globalProfiler) != 1 {
+		t.Fatalf("Expected a single active profiler, got %d", len(globalProfiler))
+	}
+}
+
+func TestCountReachablePeers(t *testing.T) {
+	probe := func(ctx context.Context, peer string) error {
+		if peer == "node3:9000" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	peers := []string{"node1:9000", "node2:9000", "node3:9000", "node4:9000"}
+	if n := countReachablePeers(context.Background(), peers, "node1:9000", probe); n != 3 {
+		t.Errorf("Expected 3 reachable nodes, got %d", n)
+	}
+	if n := countReachablePeers(context.Background(), nil, "", probe); n != 1 {
+		t.Errorf("Expected 1 node for standalone, got %d", n)
+	}
+}
+
+// unreachableEndpoint - returns an address nothing is listening on.
+func unreachableEndpoint(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	var heads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&heads, 1)
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if err := probeEndpoint(ctx, srv.URL+"/webhook", time.Second); err != nil {
+		t.Fatalf("Expected endpoint to be reachable, got %v", err)
+	}
+	if n := atomic.LoadInt32(&heads); n != 1 {
+		t.Fatalf("Expected a single HEAD request, got %d", n)
+	}
+
+	addr := unreachableEndpoint(t)
+	if err := probeEndpoint(ctx, "http://"+addr+"/webhook", time.Second); err == nil {
+		t.Fatal("Expected unreachable endpoint to fail")
+	}
+	if err := probeEndpoint(ctx, "/webhook", time.Second); err == nil {
+		t.Fatal("Expected endpoint without host to fail")
+	}
+}
+
+func TestProfilerTempDir(t *testing.T) {
+	// A path below a regular file can never be created.
+	file := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unwritable := filepath.Join(file, "tmp")
+	fallback := t.TempDir()
+
+	dir, err := makeProfilerTempDir([]string{unwritable, fallback})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(dir) != fallback {
+		t.Fatalf("Expected directory under %s, got %s", fallback, dir)
+	}
+
+	_, err = makeProfilerTempDir([]string{unwritable})
+	if err == nil || !strings.Contains(err.Error(), unwritable) {
+		t.Fatalf("Expected error naming %s, got %v", unwritable, err)
+	}
+
+	override := t.TempDir()
+	t.Setenv(config.EnvProfilerDir, override)
+	if dir, err = profilerTempDir(); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(dir) != override {
+		t.Fatalf("Expected directory under %s, got %s", override, dir)
+	}
+
+	t.Setenv(config.EnvProfilerDir, unwritable)
+	if _, err = profilerTempDir(); err == nil {
+		t.Fatal("Expected unwritable override to fail")
+	}
+}
+
+func TestRequest2BucketObjectNameSafe(t *testing.T) {
+	defer func(domains []string) { globalDomainNames = domains }(globalDomainNames)
+	globalDomainNames = []string{"mydomain.com"}
+
+	testCases := []struct {
+		host    string
+		path    string
+		bucket  string
+		object  string
+		wantErr bool
+	}{
+		// Path style.
+		{host: "localhost:9000", path: "/bucket/a/b/c", bucket: "bucket", object: "a/b/c"},
+		{host: "localhost:9000", path: "/bucket", bucket: "bucket"},
+		// Virtual-host style.
+		{host: "bucket.mydomain.com", path: "/a/b/c", bucket: "bucket", object: "a/b/c"},
+		{host: "bucket.mydomain.com:9000", path: "/", bucket: "bucket"},
+		// Malformed host.
+		{host: "bucket.mydomain.com:port", path: "/a/b/c", wantErr: true},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost"+tc.path, nil)
+		r.Host = tc.host
+		bucket, object, err := request2BucketObjectNameSafe(r)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for host %s", i+1, tc.host)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if bucket != tc.bucket || object != tc.object {
+			t.Errorf("Test %d: expected %s/%s, got %s/%s", i+1, tc.bucket, tc.object, bucket, object)
+		}
+	}
+}
+
+func TestRemoteTargetTransportResponseHeaderTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		globalAPIConfig.mu.Lock()
+		globalAPIConfig.replicationResponseHeaderTimeout = timeout
+		globalAPIConfig.mu.Unlock()
+	}(globalAPIConfig.getReplicationResponseHeaderTimeout())
+	globalAPIConfig.mu.Lock()
+	globalAPIConfig.replicationResponseHeaderTimeout = 100 * time.Millisecond
+	globalAPIConfig.mu.Unlock()
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hung" {
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		// Headers are sent at once, the body takes longer than
+		// the response header timeout.
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for i := 0; i < 3; i++ {
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte("data"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	tr := NewRemoteTargetHTTPTransport()
+	defer tr.CloseIdleConnections()
+	clnt := &http.Client{Transport: responseHeaderTimeoutTransport{
+		RoundTripper: tr,
+		timeout:      globalAPIConfig.getReplicationResponseHeaderTimeout,
+	}}
+
+	if _, err := clnt.Get(srv.URL + "/hung"); !errors.Is(err, errResponseHeaderTimeout) {
+		t.Fatalf("Expected hung target to time out, got %v", err)
+	}
+
+	// Config changes apply to the next request.
+	globalAPIConfig.mu.Lock()
+	globalAPIConfig.replicationResponseHeaderTimeout = 10 * time.Millisecond
+	globalAPIConfig.mu.Unlock()
+	start := time.Now()
+	if _, err := clnt.Get(srv.URL + "/hung"); !errors.Is(err, errResponseHeaderTimeout) {
+		t.Fatalf("Expected hung target to time out, got %v", err)
+	}
+	if d := time.Since(start); d >= 100*time.Millisecond {
+		t.Fatalf("Expected the updated timeout to apply, took %v", d)
+	}
+	globalAPIConfig.mu.Lock()
+	globalAPIConfig.replicationResponseHeaderTimeout = 100 * time.Millisecond
+	globalAPIConfig.mu.Unlock()
+
+	resp, err := clnt.Get(srv.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected slow body to be read, got %v", err)
+	}
+	if string(data) != "datadatadata" {
+		t.Fatalf("Unexpected body %q", data)
+	}
+}
+
+func TestSlowDownBackoff(t *testing.T) {
+	// Numeric Retry-After.
+	if d := slowDownBackoff(0, "3"); d != 3*time.Second {
+		t.Fatalf("Expected 3s, got %v", d)
+	}
+	if d := slowDownBackoff(5, " 120 "); d != 120*time.Second {
+		t.Fatalf("Expected 120s, got %v", d)
+	}
+
+	// HTTP-date Retry-After, second precision.
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if d := slowDownBackoff(0, date); d <= 8*time.Second || d > 10*time.Second {
+		t.Fatalf("Expected about 10s, got %v", d)
+	}
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+	if d := slowDownBackoff(0, past); d != 0 {
+		t.Fatalf("Expected no wait for a past date, got %v", d)
+	}
+
+	// No or invalid header, jittered exponential backoff.
+	for _, header := range []string{"", "soon", "-1"} {
+		prevMax := time.Duration(0)
+		for attempt := 0; attempt < 70; attempt++ {
+			expected := slowDownMaxDelay
+			if attempt < 7 {
+				expected = slowDownBaseDelay << uint(attempt)
+			}
+			d := slowDownBackoff(attempt, header)
+			if d < expected/2 || d > expected {
+				t.Fatalf("%q attempt %d: expected between %v and %v, got %v", header, attempt, expected/2, expected, d)
+			}
+			if expected < prevMax {
+				t.Fatalf("%q attempt %d: backoff decreased", header, attempt)
+			}
+			prevMax = expected
+		}
+	}
+}
+
+func TestIsInternalEndpoint(t *testing.T) {
+	prevLookupIP := lookupIP
+	defer func() { lookupIP = prevLookupIP }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "metadata.internal":
+			return []net.IP{net.ParseIP("169.254.169.254")}, nil
+		case "example.com":
+			return []net.IP{net.ParseIP("93.184.216.34")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	testCases := []struct {
+		host     string
+		internal bool
+		strict   bool
+	}{
+		{host: "127.0.0.1", internal: true, strict: true},
+		{host: "127.0.0.1:9000", internal: true, strict: true},
+		{host: "[::1]:9000", internal: true, strict: true},
+		{host: "169.254.169.254", internal: true, strict: true},
+		{host: "100.100.100.200", internal: true, strict: true},
+		{host: "0.0.0.0", internal: true, strict: true},
+		{host: "metadata.internal", internal: true, strict: true},
+		{host: "8.8.8.8", internal: false, strict: false},
+		{host: "example.com:443", internal: false, strict: false},
+		{host: "10.0.0.5", internal: false, strict: true},
+		{host: "192.168.1.10:9000", internal: false, strict: true},
+		{host: "unknown.invalid", internal: false, strict: false},
+	}
+	for _, testCase := range testCases {
+		if got := isInternalEndpoint(testCase.host); got != testCase.internal {
+			t.Errorf("isInternalEndpoint(%q) = %v, want %v", testCase.host, got, testCase.internal)
+		}
+		if got := isInternalEndpointStrict(testCase.host); got != testCase.strict {
+			t.Errorf("isInternalEndpointStrict(%q) = %v, want %v", testCase.host, got, testCase.strict)
+		}
+	}
+}
+
+type countingCertManager struct {
+	reloads int32
+}
+
+func (m *countingCertManager) ReloadCerts() {
+	atomic.AddInt32(&m.reloads, 1)
+}
+
+func TestCertReloaderCoalesce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &certReloader{window: 100 * time.Millisecond}
+	m1, m2 := &countingCertManager{}, &countingCertManager{}
+	r.managers = append(r.managers, m1, m2)
+
+	sig := make(chan os.Signal)
+	go r.run(ctx, sig)
+
+	waitReloads := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&m1.reloads) < want || atomic.LoadInt32(&m2.reloads) < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d reloads, got %d and %d", want, atomic.LoadInt32(&m1.reloads), atomic.LoadInt32(&m2.reloads))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		// Give the reloader a chance to reload more than expected.
+		time.Sleep(3 * r.window)
+		if n1, n2 := atomic.LoadInt32(&m1.reloads), atomic.LoadInt32(&m2.reloads); n1 != want || n2 != want {
+			t.Fatalf("Expected %d reloads, got %d and %d", want, n1, n2)
+		}
+	}
+
+	// Two signals in quick succession cause a single reload.
+	sig <- syscall.SIGHUP
+	sig <- syscall.SIGHUP
+	waitReloads(1)
+
+	// A later signal is never lost.
+	sig <- syscall.SIGHUP
+	waitReloads(2)
+}
+
+type recordingRoundTripper struct {
+	hosts []string
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.hosts = append(rt.hosts, req.URL.Host)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestTransportRegistryOverride(t *testing.T) {
+	prev := globalTransports.Get(transportRoleInternode)
+	defer globalTransports.Register(transportRoleInternode, func() http.RoundTripper { return prev })
+
+	rt := &recordingRoundTripper{}
+	globalTransports.Register(transportRoleInternode, func() http.RoundTripper { return rt })
+	if got := globalTransports.Get(transportRoleInternode); got != rt {
+		t.Fatalf("Expected the registered transport, got %T", got)
+	}
+
+	if err := probePeer(context.Background(), "node1:9000"); err != nil {
+		t.Fatal(err)
+	}
+	if len(rt.hosts) != 1 || rt.hosts[0] != "node1:9000" {
+		t.Fatalf("Expected the override to be used for node1:9000, got %v", rt.hosts)
+	}
+}
+
+func TestGatewayTransportOverride(t *testing.T) {
+	prev := globalTransports.Get(transportRoleGateway)
+	defer globalTransports.Register(transportRoleGateway, func() http.RoundTripper { return prev })
+
+	// Callers requiring an *http.Transport get the registered one,
+	// or the default one if another kind of transport is registered.
+	tr := NewGatewayHTTPTransport()
+	globalTransports.Register(transportRoleGateway, func() http.RoundTripper { return tr })
+	if got := gatewayTransport(); got != tr {
+		t.Fatal("Expected the registered gateway transport")
+	}
+	globalTransports.Register(transportRoleGateway, func() http.RoundTripper { return &recordingRoundTripper{} })
+	if got := gatewayTransport(); got == nil || got == tr {
+		t.Fatal("Expected the default gateway transport")
+	}
+}
+
+func TestTransportRegistryDefaults(t *testing.T) {
+	r := newTransportRegistry()
+
+	// Every role has a builder.
+	for role := transportRole(0); role < numTransportRoles; role++ {
+		if r.builders[role] == nil {
+			t.Fatalf("Expected a builder for transport role %d", role)
+		}
+	}
+
+	// Internode and proxy transports are shared.
+	for _, role := range []transportRole{transportRoleInternode, transportRoleProxy} {
+		if r.Get(role) != r.Get(role) {
+			t.Errorf("Expected the transport of role %d to be shared", role)
+		}
+	}
+
+	// Gateway and remote transports are created per call.
+	for _, role := range []transportRole{transportRoleGateway, transportRoleRemote} {
+		tr1, ok := r.Get(role).(*http.Transport)
+		if !ok {
+			t.Fatalf("Expected an *http.Transport for role %d", role)
+		}
+		if tr2 := r.Get(role).(*http.Transport); tr1 == tr2 {
+			t.Errorf("Expected a new transport per call for role %d", role)
+		}
+	}
+}
+
+func TestTLSSessionCacheResize(t *testing.T) {
+	defer func(size int64) {
+		atomic.StoreInt64(&tlsClientSessionCacheSize, size)
+	}(atomic.LoadInt64(&tlsClientSessionCacheSize))
+
+	atomic.StoreInt64(&tlsClientSessionCacheSize, 1)
+	cache := newTLSSessionCache()
+	cache.Put("a", &tls.ClientSessionState{})
+	cache.Put("b", &tls.ClientSessionState{})
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("expected session to be evicted from a cache of size 1")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Fatal("expected session to be cached")
+	}
+
+	// Caches created before the size is changed follow it.
+	atomic.StoreInt64(&tlsClientSessionCacheSize, 2)
+	cache.Put("a", &tls.ClientSessionState{})
+	cache.Put("b", &tls.ClientSessionState{})
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected session to be cached after the size was increased")
+	}
+}
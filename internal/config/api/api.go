@@ -20,11 +20,13 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/internal/config"
 	"github.com/minio/pkg/env"
 )
@@ -45,6 +47,11 @@ const (
 	apiDeleteCleanupInterval       = "delete_cleanup_interval"
 	apiDisableODirect              = "disable_odirect"
 	apiGzipObjects                 = "gzip_objects"
+	apiMaxObjectSize               = "max_object_size"
+	apiTLSSessionCacheSize         = "tls_session_cache_size"
+	apiProxyAllowedHosts           = "proxy_allowed_hosts"
+
+	apiReplicationResponseHeaderTimeout = "replication_response_header_timeout"
 
 	EnvAPIRequestsMax              = "MINIO_API_REQUESTS_MAX"
 	EnvAPIRequestsDeadline         = "MINIO_API_REQUESTS_DEADLINE"
@@ -63,6 +70,11 @@ const (
 	EnvDeleteCleanupInterval          = "MINIO_DELETE_CLEANUP_INTERVAL"
 	EnvAPIDisableODirect              = "MINIO_API_DISABLE_ODIRECT"
 	EnvAPIGzipObjects                 = "MINIO_API_GZIP_OBJECTS"
+	EnvAPIMaxObjectSize               = "MINIO_API_MAX_OBJECT_SIZE"
+	EnvAPITLSSessionCacheSize         = "MINIO_API_TLS_SESSION_CACHE_SIZE"
+	EnvAPIProxyAllowedHosts           = "MINIO_API_PROXY_ALLOWED_HOSTS"
+
+	EnvAPIReplicationResponseHeaderTimeout = "MINIO_API_REPLICATION_RESPONSE_HEADER_TIMEOUT"
 )
 
 // Deprecated key and ENVs
@@ -130,9 +142,42 @@ var (
 			Key:   apiGzipObjects,
 			Value: "off",
 		},
+		config.KV{
+			Key:   apiMaxObjectSize,
+			Value: "5TiB",
+		},
+		config.KV{
+			Key:   apiTLSSessionCacheSize,
+			Value: "100",
+		},
+		config.KV{
+			Key:   apiProxyAllowedHosts,
+			Value: "",
+		},
+		config.KV{
+			Key:   apiReplicationResponseHeaderTimeout,
+			Value: "30s",
+		},
 	}
 )
 
+// MaxObjectSize is the largest value allowed for max_object_size,
+// object sizes beyond 5TiB are not supported.
+const MaxObjectSize = 5 * humanize.TiByte
+
+// ParseTLSSessionCacheSize parses the number of TLS client sessions
+// to cache, which must be a positive integer.
+func ParseTLSSessionCacheSize(s string) (int, error) {
+	size, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("invalid value for tls session cache size, must be a positive integer")
+	}
+	return size, nil
+}
+
 // Config storage class configuration
 type Config struct {
 	RequestsMax                 int           `json:"requests_max"`
@@ -149,6 +194,11 @@ type Config struct {
 	DeleteCleanupInterval       time.Duration `json:"delete_cleanup_interval"`
 	DisableODirect              bool          `json:"disable_odirect"`
 	GzipObjects                 bool          `json:"gzip_objects"`
+	MaxObjectSize               int64         `json:"max_object_size"`
+	TLSSessionCacheSize         int           `json:"tls_session_cache_size"`
+	ProxyAllowedHosts           []string      `json:"proxy_allowed_hosts"`
+
+	ReplicationResponseHeaderTimeout time.Duration `json:"replication_response_header_timeout"`
 }
 
 // UnmarshalJSON - Validate SS and RRS parity when unmarshalling JSON.
@@ -256,6 +306,34 @@ func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 
 	gzipObjects := env.Get(EnvAPIGzipObjects, kvs.Get(apiGzipObjects)) == config.EnableOn
 
+	maxObjectSize, err := humanize.ParseBytes(env.Get(EnvAPIMaxObjectSize, kvs.GetWithDefault(apiMaxObjectSize, DefaultKVS)))
+	if err != nil {
+		return cfg, err
+	}
+	if maxObjectSize == 0 || maxObjectSize > MaxObjectSize {
+		return cfg, fmt.Errorf("invalid value for max object size, must be between 1B and %s", humanize.IBytes(MaxObjectSize))
+	}
+
+	tlsSessionCacheSize, err := ParseTLSSessionCacheSize(env.Get(EnvAPITLSSessionCacheSize, kvs.GetWithDefault(apiTLSSessionCacheSize, DefaultKVS)))
+	if err != nil {
+		return cfg, err
+	}
+
+	var proxyAllowedHosts []string
+	for _, host := range strings.Split(env.Get(EnvAPIProxyAllowedHosts, kvs.Get(apiProxyAllowedHosts)), config.ValueSeparator) {
+		if host = strings.TrimSpace(host); host != "" {
+			proxyAllowedHosts = append(proxyAllowedHosts, strings.ToLower(host))
+		}
+	}
+
+	replicationResponseHeaderTimeout, err := time.ParseDuration(env.Get(EnvAPIReplicationResponseHeaderTimeout, kvs.GetWithDefault(apiReplicationResponseHeaderTimeout, DefaultKVS)))
+	if err != nil {
+		return cfg, err
+	}
+	if replicationResponseHeaderTimeout <= 0 {
+		return cfg, fmt.Errorf("invalid value for replication response header timeout, must be a positive duration")
+	}
+
 	return Config{
 		RequestsMax:                 requestsMax,
 		RequestsDeadline:            requestsDeadline,
@@ -271,5 +349,10 @@ func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 		DeleteCleanupInterval:       deleteCleanupInterval,
 		DisableODirect:              disableODirect,
 		GzipObjects:                 gzipObjects,
+		MaxObjectSize:               int64(maxObjectSize),
+		TLSSessionCacheSize:         tlsSessionCacheSize,
+		ProxyAllowedHosts:           proxyAllowedHosts,
+
+		ReplicationResponseHeaderTimeout: replicationResponseHeaderTimeout,
 	}, nil
 }
@@ -51,10 +51,11 @@ var (
 			Type:        "csv",
 		},
 		config.HelpKV{
-			Key:         apiRemoteTransportDeadline,
-			Description: `set the deadline for API requests on remote transports while proxying between federated instances e.g. "2h"` + defaultHelpPostfix(apiRemoteTransportDeadline),
-			Optional:    true,
-			Type:        "duration",
+			Key:             apiRemoteTransportDeadline,
+			Description:     `set the deadline for API requests on remote transports while proxying between federated instances e.g. "2h", requires a restart` + defaultHelpPostfix(apiRemoteTransportDeadline),
+			Optional:        true,
+			Type:            "duration",
+			RestartRequired: true,
 		},
 		config.HelpKV{
 			Key:         apiListQuorum,
@@ -104,5 +105,30 @@ var (
 			Optional:    true,
 			Type:        "boolean",
 		},
+		config.HelpKV{
+			Key:         apiMaxObjectSize,
+			Description: `set the maximum allowed object size e.g. "100GiB", cannot exceed "5TiB"` + defaultHelpPostfix(apiMaxObjectSize),
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:             apiTLSSessionCacheSize,
+			Description:     `set the number of TLS client sessions cached for session resumption, requires a restart` + defaultHelpPostfix(apiTLSSessionCacheSize),
+			Optional:        true,
+			Type:            "number",
+			RestartRequired: true,
+		},
+		config.HelpKV{
+			Key:         apiProxyAllowedHosts,
+			Description: `set comma separated list of hosts requests may be proxied to, any host is allowed if empty` + defaultHelpPostfix(apiProxyAllowedHosts),
+			Optional:    true,
+			Type:        "csv",
+		},
+		config.HelpKV{
+			Key:         apiReplicationResponseHeaderTimeout,
+			Description: `set the maximum wait for the response headers of a replication target, object transfers are not limited` + defaultHelpPostfix(apiReplicationResponseHeaderTimeout),
+			Optional:    true,
+			Type:        "duration",
+		},
 	}
 )
@@ -36,11 +36,11 @@ type Config struct {
 // Compression environment variables
 const (
 	Extensions     = "extensions"
-	AllowEncrypted = "allow_encryption"
+	AllowEncrypted = config.CompressAllowEncrypted
 	MimeTypes      = "mime_types"
 
-	EnvCompressState           = "MINIO_COMPRESS_ENABLE"
-	EnvCompressAllowEncryption = "MINIO_COMPRESS_ALLOW_ENCRYPTION"
+	EnvCompressState           = config.EnvCompressState
+	EnvCompressAllowEncryption = config.EnvCompressAllowEncryption
 	EnvCompressExtensions      = "MINIO_COMPRESS_EXTENSIONS"
 	EnvCompressMimeTypes       = "MINIO_COMPRESS_MIME_TYPES"
 
@@ -71,20 +71,65 @@ var (
 	}
 )
 
-// Parses the given compression exclude list `extensions` or `content-types`.
+// Parses the given compression exclude list `extensions` or `content-types`,
+// entries are trimmed and lowercased.
 func parseCompressIncludes(include string) ([]string, error) {
 	includes := strings.Split(include, config.ValueSeparator)
-	for _, e := range includes {
+	for i, e := range includes {
+		e = strings.ToLower(strings.TrimSpace(e))
 		if len(e) == 0 {
 			return nil, config.ErrInvalidCompressionIncludesValue(nil).Msg("extension/mime-type cannot be empty")
 		}
 		if e == "/" {
 			return nil, config.ErrInvalidCompressionIncludesValue(nil).Msg("extension/mime-type cannot be '/'")
 		}
+		includes[i] = e
 	}
 	return includes, nil
 }
 
+// isValidMimeType - returns true if mimeType is of the form type/subtype,
+// either of which may be the wildcard '*'.
+func isValidMimeType(mimeType string) bool {
+	parts := strings.SplitN(mimeType, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Contains(parts[1], "/") {
+		return false
+	}
+	return !strings.ContainsAny(mimeType, " \t;,\"")
+}
+
+// ParseCompressionConfig - parses the comma separated extensions and
+// mime-types of the compression config, entries are trimmed and
+// lowercased and mime-types must be of the form type/subtype. Empty
+// values return no entries.
+func ParseCompressionConfig(extensions, mimeTypes string) (exts, mimes []string, err error) {
+	if extensions != "" {
+		exts, err = parseCompressIncludes(extensions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: Invalid MINIO_COMPRESS_EXTENSIONS value (`%s`)", err, extensions)
+		}
+		for _, ext := range exts {
+			if strings.Contains(ext, "/") {
+				err = config.ErrInvalidCompressionIncludesValue(nil).Msg("extension '%s' cannot contain '/'", ext)
+				return nil, nil, fmt.Errorf("%s: Invalid MINIO_COMPRESS_EXTENSIONS value (`%s`)", err, extensions)
+			}
+		}
+	}
+	if mimeTypes != "" {
+		mimes, err = parseCompressIncludes(mimeTypes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: Invalid MINIO_COMPRESS_MIME_TYPES value (`%s`)", err, mimeTypes)
+		}
+		for _, mime := range mimes {
+			if !isValidMimeType(mime) {
+				err = config.ErrInvalidCompressionIncludesValue(nil).Msg("mime-type '%s' must be of the form type/subtype", mime)
+				return nil, nil, fmt.Errorf("%s: Invalid MINIO_COMPRESS_MIME_TYPES value (`%s`)", err, mimeTypes)
+			}
+		}
+	}
+	return exts, mimes, nil
+}
+
 // LookupConfig - lookup compression config.
 func LookupConfig(kvs config.KVS) (Config, error) {
 	var err error
@@ -118,28 +163,12 @@ func LookupConfig(kvs config.KVS) (Config, error) {
 	compressExtensions := env.Get(EnvCompressExtensions, kvs.Get(Extensions))
 	compressMimeTypes := env.Get(EnvCompressMimeTypes, kvs.Get(MimeTypes))
 	compressMimeTypesLegacy := env.Get(EnvCompressMimeTypesLegacy, kvs.Get(MimeTypes))
-	if compressExtensions != "" || compressMimeTypes != "" || compressMimeTypesLegacy != "" {
-		if compressExtensions != "" {
-			extensions, err := parseCompressIncludes(compressExtensions)
-			if err != nil {
-				return cfg, fmt.Errorf("%s: Invalid MINIO_COMPRESS_EXTENSIONS value (`%s`)", err, extensions)
-			}
-			cfg.Extensions = extensions
-		}
-		if compressMimeTypes != "" {
-			mimeTypes, err := parseCompressIncludes(compressMimeTypes)
-			if err != nil {
-				return cfg, fmt.Errorf("%s: Invalid MINIO_COMPRESS_MIME_TYPES value (`%s`)", err, mimeTypes)
-			}
-			cfg.MimeTypes = mimeTypes
-		}
-		if compressMimeTypesLegacy != "" {
-			mimeTypes, err := parseCompressIncludes(compressMimeTypesLegacy)
-			if err != nil {
-				return cfg, fmt.Errorf("%s: Invalid MINIO_COMPRESS_MIME_TYPES value (`%s`)", err, mimeTypes)
-			}
-			cfg.MimeTypes = mimeTypes
-		}
+	if compressMimeTypesLegacy != "" {
+		compressMimeTypes = compressMimeTypesLegacy
+	}
+	cfg.Extensions, cfg.MimeTypes, err = ParseCompressionConfig(compressExtensions, compressMimeTypes)
+	if err != nil {
+		return cfg, err
 	}
 
 	return cfg, nil
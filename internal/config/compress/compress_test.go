@@ -56,3 +56,48 @@ func TestParseCompressIncludes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCompressionConfig(t *testing.T) {
+	testCases := []struct {
+		extensions, mimeTypes string
+		expectedExts          []string
+		expectedMimes         []string
+		success               bool
+	}{
+		{
+			extensions:    ".TXT, .log",
+			mimeTypes:     "text/*, Application/JSON",
+			expectedExts:  []string{".txt", ".log"},
+			expectedMimes: []string{"text/*", "application/json"},
+			success:       true,
+		},
+		{extensions: ".txt", expectedExts: []string{".txt"}, success: true},
+		{mimeTypes: "*/*", expectedMimes: []string{"*/*"}, success: true},
+		{success: true},
+
+		// malformed entries
+		{mimeTypes: "text/*,application", success: false},
+		{mimeTypes: "application/", success: false},
+		{mimeTypes: "application/json/x", success: false},
+		{mimeTypes: "text/plain;charset=utf-8", success: false},
+		{mimeTypes: "text/*,,application/json", success: false},
+		{extensions: ".txt,text/plain", success: false},
+	}
+
+	for i, testCase := range testCases {
+		exts, mimes, err := ParseCompressionConfig(testCase.extensions, testCase.mimeTypes)
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: expected failure for (%q, %q)", i+1, testCase.extensions, testCase.mimeTypes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected success but failed instead %s", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(testCase.expectedExts, exts) || !reflect.DeepEqual(testCase.expectedMimes, mimes) {
+			t.Errorf("Test %d: expected %v %v but got %v %v", i+1, testCase.expectedExts, testCase.expectedMimes, exts, mimes)
+		}
+	}
+}
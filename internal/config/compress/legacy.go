@@ -25,7 +25,7 @@ import (
 
 // Legacy envs.
 const (
-	EnvCompress                = "MINIO_COMPRESS"
+	EnvCompress                = config.EnvCompress
 	EnvCompressMimeTypesLegacy = "MINIO_COMPRESS_MIMETYPES"
 )
 
@@ -19,10 +19,22 @@ package config
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/minio/madmin-go"
 	"github.com/minio/minio-go/v7/pkg/set"
@@ -33,6 +45,9 @@ import (
 // Error config error type
 type Error struct {
 	Err string
+
+	// kind is one of the sentinel errors below, if any.
+	kind error
 }
 
 // Errorf - formats according to a format specifier and returns
@@ -41,10 +56,50 @@ func Errorf(format string, a ...interface{}) error {
 	return Error{Err: fmt.Sprintf(format, a...)}
 }
 
+// errorKindf - same as Errorf, the returned error matches kind with
+// errors.Is.
+func errorKindf(kind error, format string, a ...interface{}) error {
+	return Error{Err: fmt.Sprintf(format, a...), kind: kind}
+}
+
 func (e Error) Error() string {
 	return e.Err
 }
 
+// Unwrap returns the sentinel error of the fault, if any.
+func (e Error) Unwrap() error {
+	return e.kind
+}
+
+// Sentinel errors returned by GetSubSys, wrapped in a config.Error.
+var (
+	ErrEmptyInput            = errors.New("input arguments cannot be empty")
+	ErrUnknownSubSys         = errors.New("unknown sub-system")
+	ErrSingleTargetViolation = errors.New("sub-system only supports single target")
+)
+
+// ErrTooManyTargets - returned by SetKVS and friends, wrapped in a
+// config.Error, when adding a target would exceed the maximum number of
+// targets per sub-system.
+var ErrTooManyTargets = errors.New("too many targets")
+
+// ErrNotifySubSysNotAllowed - returned by SetKVS and friends and by
+// CheckNotifyAllowed, wrapped in a config.Error, when a notification
+// sub-system is not allowed as per MINIO_CONFIG_ALLOWED_NOTIFY.
+var ErrNotifySubSysNotAllowed = errors.New("notification sub-system not allowed")
+
+// DefaultMaxTargets - maximum number of targets per sub-system unless
+// changed with MINIO_CONFIG_MAX_TARGETS.
+const DefaultMaxTargets = 100
+
+// ErrDefaultCredentials - returned by LookupCreds, wrapped in a config.Error,
+// when the default credentials are refused as per MINIO_CREDENTIALS_STRICT.
+var ErrDefaultCredentials = errors.New("default credentials are not allowed")
+
+// ErrWeakCredentials - returned by LookupCreds, wrapped in a config.Error,
+// when the credentials are refused as per MINIO_CREDENTIALS_VALIDATE_STRENGTH.
+var ErrWeakCredentials = errors.New("credentials are too weak")
+
 // Default keys
 const (
 	Default = madmin.Default
@@ -207,6 +262,7 @@ const (
 	KvNewline          = madmin.KvNewline
 	KvDoubleQuote      = madmin.KvDoubleQuote
 	KvSingleQuote      = madmin.KvSingleQuote
+	KvEscape           = `\`
 
 	// Env prefix used for all envs in MinIO
 	EnvPrefix        = "MINIO_"
@@ -310,27 +366,71 @@ func (kvs KVS) String() string {
 		}
 		s.WriteString(kv.Key)
 		s.WriteString(KvSeparator)
-		spc := madmin.HasSpace(kv.Value)
-		if spc {
-			s.WriteString(KvDoubleQuote)
+		s.WriteString(quoteValue(kv.Value))
+		s.WriteString(KvSpaceSeparator)
+	}
+	return s.String()
+}
+
+// quoteValue - returns value double quoted if it would otherwise not be
+// read back as is, embedded double quotes and escape characters are
+// escaped. Reverse of unquoteValue.
+func quoteValue(value string) string {
+	if !madmin.HasSpace(value) && !strings.Contains(value, KvDoubleQuote) &&
+		!strings.HasPrefix(value, KvSingleQuote) {
+		return value
+	}
+	var s strings.Builder
+	s.WriteString(KvDoubleQuote)
+	for i := 0; i < len(value); i++ {
+		if value[i] == KvDoubleQuote[0] || value[i] == KvEscape[0] {
+			s.WriteString(KvEscape)
 		}
-		s.WriteString(kv.Value)
-		if spc {
-			s.WriteString(KvDoubleQuote)
+		s.WriteByte(value[i])
+	}
+	s.WriteString(KvDoubleQuote)
+	return s.String()
+}
+
+// unquoteValue - returns the contents of a double quoted value written
+// by quoteValue with escaped characters unescaped. Escape characters
+// not followed by a double quote or another escape character are kept.
+func unquoteValue(quoted string) string {
+	var s strings.Builder
+	for i := 0; i < len(quoted); i++ {
+		if quoted[i] == KvEscape[0] && i+1 < len(quoted) &&
+			(quoted[i+1] == KvDoubleQuote[0] || quoted[i+1] == KvEscape[0]) {
+			i++
 		}
-		s.WriteString(KvSpaceSeparator)
+		s.WriteByte(quoted[i])
 	}
 	return s.String()
 }
 
+// parseEnvTarget - extracts the target name from an environment variable
+// name listed with prefix envname. `envname` itself refers to the default
+// target, `envname_<target>` refers to <target>, target names may contain
+// the delimiter. Returns false for entries that only share a prefix with
+// envname.
+func parseEnvTarget(envListEntry, envname string) (target string, ok bool) {
+	if envListEntry == envname {
+		return Default, true
+	}
+	target = strings.TrimPrefix(envListEntry, envname+EnvWordDelimiter)
+	if target == envListEntry || target == "" {
+		return "", false
+	}
+	return target, true
+}
+
 // Merge environment values with on disk KVS, environment values overrides
 // anything on the disk.
 func Merge(cfgKVS map[string]KVS, envname string, defaultKVS KVS) map[string]KVS {
 	newCfgKVS := make(map[string]KVS)
 	for _, e := range env.List(envname) {
-		tgt := strings.TrimPrefix(e, envname+Default)
-		if tgt == envname {
-			tgt = Default
+		tgt, ok := parseEnvTarget(e, envname)
+		if !ok {
+			continue
 		}
 		newCfgKVS[tgt] = defaultKVS
 	}
@@ -386,13 +486,41 @@ func (kvs KVS) Lookup(key string) (string, bool) {
 	return "", false
 }
 
+// GetList - returns the comma separated values of key, trimmed of
+// surrounding spaces, without empty entries and duplicates.
+func (kvs KVS) GetList(key string) []string {
+	seen := set.NewStringSet()
+	values := []string{}
+	for _, v := range kvs.GetListRaw(key) {
+		if !seen.Contains(v) {
+			seen.Add(v)
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// GetListRaw - same as GetList but preserves duplicates, values are
+// returned in the configured order.
+func (kvs KVS) GetListRaw(key string) []string {
+	values := []string{}
+	for _, v := range strings.Split(kvs.Get(key), ValueSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 // Config - MinIO server config structure.
 type Config map[string]map[string]KVS
 
 // DelFrom - deletes all keys in the input reader.
 func (c Config) DelFrom(r io.Reader) error {
-	scanner := bufio.NewScanner(r)
+	var lineNum int
+	scanner := newConfigScanner(r)
 	for scanner.Scan() {
+		lineNum++
 		// Skip any empty lines, or comment like characters
 		text := scanner.Text()
 		if text == "" || strings.HasPrefix(text, KvComment) {
@@ -402,16 +530,40 @@ func (c Config) DelFrom(r io.Reader) error {
 			return err
 		}
 	}
-	return scanner.Err()
+	return configScanErr(scanner.Err(), lineNum+1)
+}
+
+// MaxConfigLineSize - maximum length of a single line read by
+// ReadConfig and DelFrom.
+var MaxConfigLineSize = 1 << 20
+
+// ErrConfigLineTooLong - returned by ReadConfig and DelFrom, wrapped in
+// a config.Error, for a line longer than MaxConfigLineSize.
+var ErrConfigLineTooLong = errors.New("config line too long")
+
+func newConfigScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxConfigLineSize)
+	return scanner
+}
+
+// configScanErr - converts err of a config scanner failing to read
+// lineNum into a clear error.
+func configScanErr(err error, lineNum int) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return errorKindf(ErrConfigLineTooLong, "line %d: config line too long, lines must not exceed %d bytes", lineNum, MaxConfigLineSize)
+	}
+	return err
 }
 
 // ReadConfig - read content from input and write into c.
 // Returns whether all parameters were dynamic.
 func (c Config) ReadConfig(r io.Reader) (dynOnly bool, err error) {
-	var n int
-	scanner := bufio.NewScanner(r)
+	var n, lineNum int
+	scanner := newConfigScanner(r)
 	dynOnly = true
 	for scanner.Scan() {
+		lineNum++
 		// Skip any empty lines, or comment like characters
 		text := scanner.Text()
 		if text == "" || strings.HasPrefix(text, KvComment) {
@@ -419,17 +571,161 @@ func (c Config) ReadConfig(r io.Reader) (dynOnly bool, err error) {
 		}
 		dynamic, err := c.SetKVS(text, DefaultKVS)
 		if err != nil {
-			return false, err
+			var cfgErr Error
+			if errors.As(err, &cfgErr) {
+				// Preserve the error type, callers rely on it.
+				return false, errorKindf(cfgErr.kind, "line %d `%s`: %s", lineNum, redactConfigLine(text), cfgErr.Err)
+			}
+			return false, fmt.Errorf("line %d `%s`: %w", lineNum, redactConfigLine(text), err)
 		}
 		dynOnly = dynOnly && dynamic
 		n += len(text)
 	}
-	if err := scanner.Err(); err != nil {
+	if err := configScanErr(scanner.Err(), lineNum+1); err != nil {
 		return false, err
 	}
 	return dynOnly, nil
 }
 
+// redactConfigLine - returns the config line with the values
+// of all sensitive keys redacted, used for error reporting.
+func redactConfigLine(text string) string {
+	subSys, inputs, _, err := GetSubSys(text)
+	if err != nil || len(inputs) < 2 {
+		return text
+	}
+	hkvs := HelpSubSysMap[subSys]
+	fields := madmin.KvFields(inputs[1], append(DefaultKVS[subSys].Keys(), hkvs.keys()...))
+	if len(fields) == 0 {
+		// No known keys, hence nothing sensitive.
+		return text
+	}
+	redacted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		kv := strings.SplitN(field, KvSeparator, 2)
+		if hkv, ok := hkvs.Lookup(kv[0]); ok && hkv.Sensitive && len(kv) == 2 {
+			field = kv[0] + KvSeparator + "*redacted*"
+		}
+		redacted = append(redacted, field)
+	}
+	return inputs[0] + KvSpaceSeparator + strings.Join(redacted, KvSpaceSeparator)
+}
+
+// SensitiveKeys - returns the keys of subSys marked as sensitive
+// in its help, empty for unknown sub-systems.
+func SensitiveKeys(subSys string) []string {
+	keys := []string{}
+	for _, hkv := range HelpSubSysMap[subSys] {
+		if hkv.Sensitive {
+			keys = append(keys, hkv.Key)
+		}
+	}
+	return keys
+}
+
+// redactedValue - replaces sensitive values when redacting.
+const redactedValue = "*redacted*"
+
+// secretQueryParams - query parameters masked by RedactURLCredentials.
+var secretQueryParams = set.CreateStringSet(
+	"access_token",
+	"api_key",
+	"apikey",
+	"auth_token",
+	"password",
+	"secret",
+	"secret_key",
+	"signature",
+	"token",
+	"x-amz-credential",
+	"x-amz-security-token",
+	"x-amz-signature",
+)
+
+// RedactURLCredentials - returns raw with the userinfo and the values of
+// known secret query parameters masked. Anything that does not parse as
+// an absolute URL is redacted altogether.
+func RedactURLCredentials(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redactedValue
+	}
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			key := strings.SplitN(param, "=", 2)[0]
+			if k, err := url.QueryUnescape(key); err == nil && secretQueryParams.Contains(strings.ToLower(k)) {
+				params[i] = key + "=" + redactedValue
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+	if u.User == nil {
+		return u.String()
+	}
+	// url.User escapes the redacted value, add it by hand.
+	u.User = nil
+	return strings.Replace(u.String(), "://", "://"+redactedValue+"@", 1)
+}
+
+// SplitHostPort - splits an endpoint config value into host and port,
+// the endpoint may be a URL, a bracketed IPv6 literal or a host with or
+// without a port. The port is empty when the endpoint has none, IPv6
+// hosts are returned without brackets.
+func SplitHostPort(endpoint string) (host, port string, err error) {
+	endpoint = strings.TrimSpace(endpoint)
+	hostport := endpoint
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", "", Errorf("invalid endpoint %q: %v", endpoint, err)
+		}
+		hostport = u.Host
+	}
+
+	switch {
+	case strings.HasPrefix(hostport, "["):
+		end := strings.IndexByte(hostport, ']')
+		if end < 0 {
+			return "", "", Errorf("invalid endpoint %q: missing ']' in address", endpoint)
+		}
+		host = hostport[1:end]
+		if rest := hostport[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return "", "", Errorf("invalid endpoint %q: unexpected %q after address", endpoint, rest)
+			}
+			port = rest[1:]
+			if port == "" {
+				return "", "", Errorf("invalid endpoint %q: empty port", endpoint)
+			}
+		}
+	case strings.Count(hostport, ":") > 1:
+		// IPv6 literal without brackets, cannot carry a port.
+		host = hostport
+	default:
+		host = hostport
+		if i := strings.Index(hostport, ":"); i >= 0 {
+			host, port = hostport[:i], hostport[i+1:]
+		}
+		if port == "" && strings.HasSuffix(hostport, ":") {
+			return "", "", Errorf("invalid endpoint %q: empty port", endpoint)
+		}
+	}
+
+	if host == "" {
+		return "", "", Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+	if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+		return "", "", Errorf("invalid endpoint %q: invalid IPv6 address %q", endpoint, host)
+	}
+	if port != "" {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return "", "", Errorf("invalid endpoint %q: invalid port %q", endpoint, port)
+		}
+	}
+	return host, port, nil
+}
+
 // RedactSensitiveInfo - removes sensitive information
 // like urls and credentials from the configuration
 func (c Config) RedactSensitiveInfo() Config {
@@ -459,6 +755,7 @@ func (c Config) RedactSensitiveInfo() Config {
 type configWriteTo struct {
 	Config
 	filterByKey string
+	nonDefault  bool
 }
 
 // NewConfigWriteTo - returns a struct which
@@ -468,12 +765,45 @@ func NewConfigWriteTo(cfg Config, key string) io.WriterTo {
 	return &configWriteTo{Config: cfg, filterByKey: key}
 }
 
+// NewConfigWriteToNonDefault - same as NewConfigWriteTo but only
+// serializes the keys whose values differ from their defaults,
+// targets without such keys are omitted.
+func NewConfigWriteToNonDefault(cfg Config, key string) io.WriterTo {
+	return &configWriteTo{Config: cfg, filterByKey: key, nonDefault: true}
+}
+
+// nonDefaultTargets - returns targets with only the keys whose values
+// differ from the defaults, keys without defaults such as comment are
+// kept if set.
+func nonDefaultTargets(targets Targets) Targets {
+	filtered := make(Targets, 0, len(targets))
+	for _, target := range targets {
+		subSys := strings.SplitN(target.SubSystem, SubSystemSeparator, 2)[0]
+		var kvs KVS
+		for _, kv := range target.KVS {
+			if defValue, ok := DefaultKVS[subSys].Lookup(kv.Key); ok && kv.Value == defValue {
+				continue
+			} else if !ok && kv.Value == "" {
+				continue
+			}
+			kvs = append(kvs, kv)
+		}
+		if len(kvs) > 0 {
+			filtered = append(filtered, Target{SubSystem: target.SubSystem, KVS: kvs})
+		}
+	}
+	return filtered
+}
+
 // WriteTo - implements io.WriterTo interface implementation for config.
 func (c *configWriteTo) WriteTo(w io.Writer) (int64, error) {
 	kvsTargets, err := c.GetKVS(c.filterByKey, DefaultKVS)
 	if err != nil {
 		return 0, err
 	}
+	if c.nonDefault {
+		kvsTargets = nonDefaultTargets(kvsTargets)
+	}
 	var n int
 	for _, target := range kvsTargets {
 		m1, _ := w.Write([]byte(target.SubSystem))
@@ -489,6 +819,22 @@ func (c *configWriteTo) WriteTo(w io.Writer) (int64, error) {
 	return int64(n), nil
 }
 
+// EstimateSerializedSize - returns the approximate size in bytes of the
+// config in the text format written by NewConfigWriteTo, without
+// serializing it.
+func (c Config) EstimateSerializedSize() int64 {
+	var n int
+	for subSys, tgtKVS := range c {
+		for tgt, kvs := range tgtKVS {
+			n += len(subSys) + len(KvSpaceSeparator) + len(kvs.String()) + len(KvNewline)
+			if tgt != Default {
+				n += len(SubSystemSeparator) + len(tgt)
+			}
+		}
+	}
+	return int64(n)
+}
+
 // Default KV configs for worm and region
 var (
 	DefaultCredentialKVS = KVS{
@@ -521,20 +867,77 @@ var (
 	}
 )
 
-// LookupCreds - lookup credentials from config.
+// LookupCreds - lookup credentials from config, falls back to the
+// default credentials if none are set unless MINIO_CREDENTIALS_STRICT
+// is enabled.
 func LookupCreds(kv KVS) (auth.Credentials, error) {
 	if err := CheckValidKeys(CredentialsSubSys, kv, DefaultCredentialKVS); err != nil {
 		return auth.Credentials{}, err
 	}
+	strict, err := ParseBool(env.Get(EnvCredentialsStrict, EnableOff))
+	if err != nil {
+		return auth.Credentials{}, err
+	}
 	accessKey := kv.Get(AccessKey)
 	secretKey := kv.Get(SecretKey)
 	if accessKey == "" || secretKey == "" {
 		accessKey = auth.DefaultAccessKey
 		secretKey = auth.DefaultSecretKey
 	}
+	if strict && accessKey == auth.DefaultAccessKey && secretKey == auth.DefaultSecretKey {
+		return auth.Credentials{}, errorKindf(ErrDefaultCredentials, "default credentials are not allowed with %s, please configure '%s' and '%s'",
+			EnvCredentialsStrict, AccessKey, SecretKey)
+	}
+	if err = lookupCredentialStrength(accessKey, secretKey); err != nil {
+		return auth.Credentials{}, err
+	}
 	return auth.CreateCredentials(accessKey, secretKey)
 }
 
+// lookupCredentialStrength - validates the strength of the credentials
+// if enabled with MINIO_CREDENTIALS_VALIDATE_STRENGTH.
+func lookupCredentialStrength(accessKey, secretKey string) error {
+	validate, err := ParseBool(env.Get(EnvCredentialsValidateStrength, EnableOff))
+	if err != nil || !validate {
+		return err
+	}
+	var minAccessKeyLen, minSecretKeyLen int
+	if v := env.Get(EnvCredentialsMinAccessKeyLength, ""); v != "" {
+		if minAccessKeyLen, err = strconv.Atoi(v); err != nil {
+			return Errorf("invalid %s: %v", EnvCredentialsMinAccessKeyLength, err)
+		}
+	}
+	if v := env.Get(EnvCredentialsMinSecretKeyLength, ""); v != "" {
+		if minSecretKeyLen, err = strconv.Atoi(v); err != nil {
+			return Errorf("invalid %s: %v", EnvCredentialsMinSecretKeyLength, err)
+		}
+	}
+	return validateCredentialStrength(accessKey, secretKey, minAccessKeyLen, minSecretKeyLen)
+}
+
+// validateCredentialStrength - returns an error if the keys are shorter
+// than the lengths allowed by auth or the given minimums, or if the
+// secret key is trivially guessable.
+func validateCredentialStrength(accessKey, secretKey string, minAccessKeyLen, minSecretKeyLen int) error {
+	if !auth.IsAccessKeyValid(accessKey) {
+		return errorKindf(ErrWeakCredentials, "'%s': %v", AccessKey, auth.ErrInvalidAccessKeyLength)
+	}
+	if !auth.IsSecretKeyValid(secretKey) {
+		return errorKindf(ErrWeakCredentials, "'%s': %v", SecretKey, auth.ErrInvalidSecretKeyLength)
+	}
+	if len(accessKey) < minAccessKeyLen {
+		return errorKindf(ErrWeakCredentials, "'%s' must be at least %d characters long", AccessKey, minAccessKeyLen)
+	}
+	if len(secretKey) < minSecretKeyLen {
+		return errorKindf(ErrWeakCredentials, "'%s' must be at least %d characters long", SecretKey, minSecretKeyLen)
+	}
+	if secretKey == accessKey || secretKey == auth.DefaultSecretKey ||
+		strings.Count(secretKey, secretKey[:1]) == len(secretKey) {
+		return errorKindf(ErrWeakCredentials, "'%s' is too weak, please use a randomly generated value", SecretKey)
+	}
+	return nil
+}
+
 // Site - holds site info - name and region.
 type Site struct {
 	Name   string
@@ -547,6 +950,40 @@ var validRegionRegex = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9-_-]+$")
 // letter. At least 2 characters long.
 var validSiteNameRegex = regexp.MustCompile("^[a-z][a-z0-9-]+$")
 
+// NewSite - returns a Site with the given name and region, either of
+// which may be empty, validated like the values read by LookupSite.
+func NewSite(name, region string) (Site, error) {
+	if region != "" {
+		if err := validateSiteRegion(region); err != nil {
+			return Site{}, err
+		}
+	}
+	if name != "" {
+		if err := validateSiteName(name); err != nil {
+			return Site{}, err
+		}
+	}
+	return Site{Name: name, Region: region}, nil
+}
+
+func validateSiteRegion(region string) error {
+	if !validRegionRegex.MatchString(region) {
+		return Errorf(
+			"region '%s' is invalid, expected simple characters such as [us-east-1, myregion...]",
+			region)
+	}
+	return nil
+}
+
+func validateSiteName(name string) error {
+	if !validSiteNameRegex.MatchString(name) {
+		return Errorf(
+			"site name '%s' is invalid, expected simple characters such as [cal-rack0, myname...]",
+			name)
+	}
+	return nil
+}
+
 // LookupSite - get site related configuration. Loads configuration from legacy
 // region sub-system as well.
 func LookupSite(siteKV KVS, regionKV KVS) (s Site, err error) {
@@ -575,28 +1012,83 @@ func LookupSite(siteKV KVS, regionKV KVS) (s Site, err error) {
 		region = regionKV.Get(RegionName)
 	}
 	if region != "" {
-		if !validRegionRegex.MatchString(region) {
-			err = Errorf(
-				"region '%s' is invalid, expected simple characters such as [us-east-1, myregion...]",
-				region)
+		// Region may be read from a file, the file contents are
+		// validated like any other region.
+		resolved, rerr := resolveValueFrom(RegionKey, region)
+		if rerr != nil {
+			err = Errorf("region '%s' is invalid, %v", region, rerr)
+			return
+		}
+		if resolved != region {
+			// Only name the source, the file contents are not
+			// echoed back in errors.
+			if !validRegionRegex.MatchString(resolved) {
+				err = Errorf("'%s' read from '%s' is invalid, expected simple characters such as [us-east-1, myregion...]",
+					RegionKey, region)
+				return
+			}
+		} else if err = validateSiteRegion(region); err != nil {
 			return
 		}
-		s.Region = region
+		s.Region = resolved
 	}
 
 	name := env.Get(EnvSiteName, siteKV.Get(NameKey))
 	if name != "" {
-		if !validSiteNameRegex.MatchString(name) {
-			err = Errorf(
-				"site name '%s' is invalid, expected simple characters such as [cal-rack0, myname...]",
-				name)
+		if err = validateSiteName(name); err != nil {
 			return
 		}
 		s.Name = name
 	}
+	if s.Name == "" {
+		var autoName bool
+		autoName, err = ParseBool(env.Get(EnvSiteAutoName, EnableOff))
+		if err != nil {
+			err = Errorf("invalid value for %s: %v", EnvSiteAutoName, err)
+			return
+		}
+		if autoName {
+			s.Name = siteNameFromHostname()
+		}
+	}
 	return
 }
 
+// siteHostname returns the hostname a site name is derived from, it
+// is the hostname of this node unless pinned by SetSiteHostname.
+var siteHostname = os.Hostname
+
+// SetSiteHostname pins the hostname a site name is derived from. In a
+// distributed setup every node must derive the same site name, so a
+// hostname shared by the whole cluster must be used instead of the
+// hostname of each node.
+func SetSiteHostname(host string) {
+	siteHostname = func() (string, error) { return host, nil }
+}
+
+// siteNameFromHostname - derives a site name from the first label of
+// the site hostname, characters not allowed in a site name are replaced
+// by '-'. Returns "" if no valid site name can be derived.
+func siteNameFromHostname() string {
+	host, err := siteHostname()
+	if err != nil {
+		return ""
+	}
+	host = strings.SplitN(strings.ToLower(host), ".", 2)[0]
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, host)
+	name = strings.TrimLeft(name, "0123456789-")
+	name = strings.TrimRight(name, "-")
+	if !validSiteNameRegex.MatchString(name) {
+		return ""
+	}
+	return name
+}
+
 // CheckValidKeys - checks if inputs KVS has the necessary keys,
 // returns error if it find extra or superflous keys.
 func CheckValidKeys(subSys string, kv KVS, validKVS KVS) error {
@@ -631,98 +1123,382 @@ var renamedSubsys = map[string]string{
 	// Add future sub-system renames
 }
 
-// Merge - merges a new config with all the
-// missing values for default configs,
-// returns a config.
-func (c Config) Merge() Config {
-	cp := New()
-	for subSys, tgtKV := range c {
-		for tgt := range tgtKV {
-			ckvs := c[subSys][tgt]
-			for _, kv := range cp[subSys][Default] {
-				_, ok := c[subSys][tgt].Lookup(kv.Key)
-				if !ok {
-					ckvs.Set(kv.Key, kv.Value)
-				}
-			}
-			if _, ok := cp[subSys]; !ok {
-				rnSubSys, ok := renamedSubsys[subSys]
-				if !ok {
-					// A config subsystem was removed or server was downgraded.
+// IsDynamic - returns true if changes to subSys, or to the sub-system
+// it was renamed to, are applied without a restart.
+func IsDynamic(subSys string) bool {
+	if rnSubSys, ok := renamedSubsys[subSys]; ok {
+		subSys = rnSubSys
+	}
+	return SubSystemsDynamic.Contains(subSys)
+}
+
+// CollectDeprecationWarnings - returns warnings for every sub-system still
+// configured under its old name and for every deprecated key present in
+// the config, deprecated is a map of sub-system to its deprecated keys.
+func (c Config) CollectDeprecationWarnings(deprecated map[string][]string) []string {
+	var warnings []string
+	for subSys, tgtKVS := range c {
+		if rnSubSys, ok := renamedSubsys[subSys]; ok && len(tgtKVS) > 0 {
+			warnings = append(warnings, fmt.Sprintf("sub-system '%s' is deprecated, please use '%s' instead", subSys, rnSubSys))
+		}
+		for _, key := range deprecated[subSys] {
+			for tgt, kvs := range tgtKVS {
+				if _, ok := kvs.Lookup(key); !ok {
 					continue
 				}
-				// Copy over settings from previous sub-system
-				// to newly renamed sub-system
-				for _, kv := range cp[rnSubSys][Default] {
-					_, ok := c[subSys][tgt].Lookup(kv.Key)
-					if !ok {
-						ckvs.Set(kv.Key, kv.Value)
-					}
+				subSysTgt := subSys
+				if tgt != Default {
+					subSysTgt += SubSystemSeparator + tgt
 				}
-				subSys = rnSubSys
+				warnings = append(warnings, fmt.Sprintf("key '%s' in '%s' is deprecated and will be removed in a future release", key, subSysTgt))
 			}
-			cp[subSys][tgt] = ckvs
 		}
 	}
-	return cp
+	sort.Strings(warnings)
+	return warnings
 }
 
-// New - initialize a new server config.
-func New() Config {
-	srvCfg := make(Config)
-	for _, k := range SubSystems.ToSlice() {
-		srvCfg[k] = map[string]KVS{}
-		srvCfg[k][Default] = DefaultKVS[k]
+// ValidateEncryptionConsistency - cross-checks the KMS and encryption
+// settings and returns an error describing the first inconsistent
+// combination, such as auto-encryption enabled without a KMS. The KMS is
+// configured by the environment only, the compression settings of c are
+// checked once stored, the environment taking precedence over them.
+// Passes if encryption is off or fully configured.
+func (c Config) ValidateEncryptionConsistency() error {
+	kmsSecretKey := env.IsSet(EnvKMSSecretKey) || env.IsSet(EnvKMSSecretKeyFile)
+	kes := env.IsSet(EnvKESEndpoint)
+	if kmsSecretKey && kes {
+		return Errorf("ambiguous KMS configuration, only one of %s or %s can be set", EnvKMSSecretKey, EnvKESEndpoint)
+	}
+	if kes {
+		for _, envVar := range []string{EnvKESKeyName, EnvKESClientCert, EnvKESClientKey} {
+			if env.Get(envVar, "") == "" {
+				return Errorf("%s requires %s to be set", EnvKESEndpoint, envVar)
+			}
+		}
 	}
-	return srvCfg
-}
-
-// Target signifies an individual target
-type Target struct {
-	SubSystem string
-	KVS       KVS
-}
 
-// Targets sub-system targets
-type Targets []Target
+	autoEncryption, err := ParseBool(env.Get(EnvKMSAutoEncryption, EnableOff))
+	if err != nil {
+		return Errorf("invalid value for %s: %v", EnvKMSAutoEncryption, err)
+	}
+	if autoEncryption && !kmsSecretKey && !kes {
+		return Errorf("%s requires a KMS, set either %s or %s", EnvKMSAutoEncryption, EnvKMSSecretKey, EnvKESEndpoint)
+	}
 
-// GetKVS - get kvs from specific subsystem.
-func (c Config) GetKVS(s string, defaultKVS map[string]KVS) (Targets, error) {
-	if len(s) == 0 {
-		return nil, Errorf("input cannot be empty")
+	kvs, ok := c[CompressionSubSys][Default]
+	if !ok {
+		return nil
 	}
-	inputs := strings.Fields(s)
-	if len(inputs) > 1 {
-		return nil, Errorf("invalid number of arguments %s", s)
+	compress := env.Get(EnvCompress, "")
+	if compress == "" {
+		compress = env.Get(EnvCompressState, kvs.Get(Enable))
 	}
-	subSystemValue := strings.SplitN(inputs[0], SubSystemSeparator, 2)
-	if len(subSystemValue) == 0 {
-		return nil, Errorf("invalid number of arguments %s", s)
+	allowEncrypted := env.Get(EnvCompressAllowEncryption, kvs.Get(CompressAllowEncrypted))
+	if compress == "" || allowEncrypted == "" {
+		return nil
 	}
-	found := SubSystems.Contains(subSystemValue[0])
-	if !found {
-		// Check for sub-prefix only if the input value is only a
-		// single value, this rejects invalid inputs if any.
-		found = !SubSystems.FuncMatch(strings.HasPrefix, subSystemValue[0]).IsEmpty() && len(subSystemValue) == 1
+	compressEnabled, err := ParseBool(compress)
+	if err != nil {
+		return Errorf("invalid value for %s:%s: %v", CompressionSubSys, Enable, err)
 	}
-	if !found {
-		return nil, Errorf("unknown sub-system %s", s)
+	allowEnc, err := ParseBool(allowEncrypted)
+	if err != nil {
+		return Errorf("invalid value for %s:%s: %v", CompressionSubSys, CompressAllowEncrypted, err)
 	}
+	if allowEnc && !compressEnabled {
+		return Errorf("%s:%s requires compression to be enabled", CompressionSubSys, CompressAllowEncrypted)
+	}
+	return nil
+}
 
-	targets := Targets{}
-	subSysPrefix := subSystemValue[0]
-	if len(subSystemValue) == 2 {
-		if len(subSystemValue[1]) == 0 {
-			return nil, Errorf("sub-system target '%s' cannot be empty", s)
-		}
-		kvs, ok := c[subSysPrefix][subSystemValue[1]]
-		if !ok {
-			return nil, Errorf("sub-system target '%s' doesn't exist", s)
-		}
-		for _, kv := range defaultKVS[subSysPrefix] {
-			_, ok = kvs.Lookup(kv.Key)
-			if !ok {
-				kvs.Set(kv.Key, kv.Value)
+// RestartRequiredAfter - returns true if applied differs from c in any
+// sub-system that is not dynamic, or in any key of a dynamic sub-system
+// that RequiresRestart, i.e. the server must be restarted for applied
+// to take effect.
+func (c Config) RestartRequiredAfter(applied Config) bool {
+	subSystems := set.NewStringSet()
+	for subSys := range c {
+		subSystems.Add(subSys)
+	}
+	for subSys := range applied {
+		subSystems.Add(subSys)
+	}
+	for subSys := range subSystems {
+		if IsDynamic(subSys) {
+			if c.changedKeysRequireRestart(subSys, applied) {
+				return true
+			}
+			continue
+		}
+		if !equalTargets(c[subSys], applied[subSys]) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedKeysRequireRestart - returns true if any key of subSys that
+// differs between c and applied RequiresRestart, keys missing on either
+// side are compared with their default values.
+func (c Config) changedKeysRequireRestart(subSys string, applied Config) bool {
+	defaultKVS := DefaultKVS[subSys]
+	if rnSubSys, ok := renamedSubsys[subSys]; ok {
+		defaultKVS = DefaultKVS[rnSubSys]
+	}
+	get := func(kvs KVS, key string) string {
+		if v, ok := kvs.Lookup(key); ok {
+			return v
+		}
+		return defaultKVS.Get(key)
+	}
+	targets := set.NewStringSet()
+	for tgt := range c[subSys] {
+		targets.Add(tgt)
+	}
+	for tgt := range applied[subSys] {
+		targets.Add(tgt)
+	}
+	for tgt := range targets {
+		old, new := c[subSys][tgt], applied[subSys][tgt]
+		keys := set.NewStringSet()
+		for _, kv := range old {
+			keys.Add(kv.Key)
+		}
+		for _, kv := range new {
+			keys.Add(kv.Key)
+		}
+		for key := range keys {
+			if get(old, key) != get(new, key) && c.RequiresRestart(subSys, tgt, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequiresRestart - returns true if a change to key of the given
+// sub-system target only takes effect after a restart. Keys of
+// dynamic sub-systems are hot-applicable unless their help marks
+// them as RestartRequired, all targets share the same help.
+func (c Config) RequiresRestart(subSys, target, key string) bool {
+	if !IsDynamic(subSys) {
+		return true
+	}
+	if rnSubSys, ok := renamedSubsys[subSys]; ok {
+		subSys = rnSubSys
+	}
+	hkv, ok := HelpSubSysMap[subSys].Lookup(key)
+	return ok && hkv.RestartRequired
+}
+
+// equalTargets - returns true if a and b have the same targets
+// with the same key values, regardless of the order of the keys.
+func equalTargets(a, b map[string]KVS) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for tgt, akvs := range a {
+		bkvs, ok := b[tgt]
+		if !ok || len(akvs) != len(bkvs) {
+			return false
+		}
+		for _, kv := range akvs {
+			if v, ok := bkvs.Lookup(kv.Key); !ok || v != kv.Value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Checksum - returns a stable hash of the config, with missing keys
+// backfilled from their defaults, so that configs can be compared
+// cheaply. The result does not depend on the order of sub-systems,
+// targets or keys.
+func (c Config) Checksum() string {
+	subSystems := make([]string, 0, len(c))
+	for subSys := range c {
+		subSystems = append(subSystems, subSys)
+	}
+	sort.Strings(subSystems)
+
+	h := sha256.New()
+	for _, subSys := range subSystems {
+		targets := make([]string, 0, len(c[subSys]))
+		for tgt := range c[subSys] {
+			targets = append(targets, tgt)
+		}
+		sort.Strings(targets)
+
+		for _, tgt := range targets {
+			values := effectiveValues(subSys, c[subSys][tgt])
+			for _, key := range sortedValueKeys(values) {
+				fmt.Fprintf(h, "%q %q %q %q\n", subSys, tgt, key, values[key])
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SafeFingerprint - same as Checksum but computed over the config with
+// sensitive values redacted and the credentials removed, so it can be
+// logged and compared across nodes. Configs which only differ in the
+// values of secrets have the same fingerprint, whether a secret is set
+// or not still changes it.
+func (c Config) SafeFingerprint() string {
+	return c.RedactSensitiveInfo().Checksum()
+}
+
+// effectiveValues - returns the values of kvs with missing keys
+// backfilled from the defaults of subSys.
+func effectiveValues(subSys string, kvs KVS) map[string]string {
+	values := make(map[string]string, len(DefaultKVS[subSys])+len(kvs))
+	for _, kv := range DefaultKVS[subSys] {
+		values[kv.Key] = kv.Value
+	}
+	for _, kv := range kvs {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func sortedValueKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FindDuplicateTargets - returns the groups of enabled targets of subSys
+// sharing the same effective configuration, e.g. two webhook targets
+// with the same endpoint which would receive every event twice. Target
+// names and comments are ignored in the comparison.
+func (c Config) FindDuplicateTargets(subSys string) [][]string {
+	groups := make(map[string][]string)
+	for tgt, kvs := range c[subSys] {
+		values := effectiveValues(subSys, kvs)
+		if v, ok := values[Enable]; ok {
+			if enabled, err := ParseBool(v); err != nil || !enabled {
+				continue
+			}
+		}
+		delete(values, Comment)
+
+		var sb strings.Builder
+		for _, key := range sortedValueKeys(values) {
+			fmt.Fprintf(&sb, "%q %q\n", key, values[key])
+		}
+		groups[sb.String()] = append(groups[sb.String()], tgt)
+	}
+
+	duplicates := [][]string{}
+	for _, targets := range groups {
+		if len(targets) > 1 {
+			sort.Strings(targets)
+			duplicates = append(duplicates, targets)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i][0] < duplicates[j][0]
+	})
+	return duplicates
+}
+
+// Merge - merges a new config with all the
+// missing values for default configs,
+// returns a config.
+func (c Config) Merge() Config {
+	cp := New()
+	for subSys, tgtKV := range c {
+		for tgt := range tgtKV {
+			ckvs := c[subSys][tgt]
+			for _, kv := range cp[subSys][Default] {
+				_, ok := c[subSys][tgt].Lookup(kv.Key)
+				if !ok {
+					ckvs.Set(kv.Key, kv.Value)
+				}
+			}
+			if _, ok := cp[subSys]; !ok {
+				rnSubSys, ok := renamedSubsys[subSys]
+				if !ok {
+					// A config subsystem was removed or server was downgraded.
+					continue
+				}
+				// Copy over settings from previous sub-system
+				// to newly renamed sub-system
+				for _, kv := range cp[rnSubSys][Default] {
+					_, ok := c[subSys][tgt].Lookup(kv.Key)
+					if !ok {
+						ckvs.Set(kv.Key, kv.Value)
+					}
+				}
+				subSys = rnSubSys
+			}
+			cp[subSys][tgt] = ckvs
+		}
+	}
+	return cp
+}
+
+// New - initialize a new server config.
+func New() Config {
+	srvCfg := make(Config)
+	for _, k := range SubSystems.ToSlice() {
+		srvCfg[k] = map[string]KVS{}
+		srvCfg[k][Default] = DefaultKVS[k]
+	}
+	return srvCfg
+}
+
+// Target signifies an individual target
+type Target struct {
+	SubSystem string
+	KVS       KVS
+}
+
+// Targets sub-system targets
+type Targets []Target
+
+// GetKVS - get kvs from specific subsystem.
+func (c Config) GetKVS(s string, defaultKVS map[string]KVS) (Targets, error) {
+	if len(s) == 0 {
+		return nil, Errorf("input cannot be empty")
+	}
+	inputs := strings.Fields(s)
+	if len(inputs) > 1 {
+		return nil, Errorf("invalid number of arguments %s", s)
+	}
+	subSystemValue := strings.SplitN(inputs[0], SubSystemSeparator, 2)
+	if len(subSystemValue) == 0 {
+		return nil, Errorf("invalid number of arguments %s", s)
+	}
+	found := SubSystems.Contains(subSystemValue[0])
+	if !found {
+		// Check for sub-prefix only if the input value is only a
+		// single value, this rejects invalid inputs if any.
+		found = !SubSystems.FuncMatch(strings.HasPrefix, subSystemValue[0]).IsEmpty() && len(subSystemValue) == 1
+	}
+	if !found {
+		return nil, Errorf("unknown sub-system %s", s)
+	}
+
+	targets := Targets{}
+	subSysPrefix := subSystemValue[0]
+	if len(subSystemValue) == 2 {
+		if len(subSystemValue[1]) == 0 {
+			return nil, Errorf("sub-system target '%s' cannot be empty", s)
+		}
+		kvs, ok := c[subSysPrefix][subSystemValue[1]]
+		if !ok {
+			return nil, Errorf("sub-system target '%s' doesn't exist", s)
+		}
+		for _, kv := range defaultKVS[subSysPrefix] {
+			_, ok = kvs.Lookup(kv.Key)
+			if !ok {
+				kvs.Set(kv.Key, kv.Value)
 			}
 		}
 		targets = append(targets, Target{
@@ -817,18 +1593,139 @@ func (c Config) Clone() Config {
 	return cp
 }
 
+// Overlay - returns a copy of c with other layered on top, targets only
+// present in other are added. For targets present in both, keys missing
+// in c are added, existing keys are replaced by the value in other only
+// if overwrite is set. Neither c nor other is modified.
+func (c Config) Overlay(other Config, overwrite bool) Config {
+	cp := c.Clone()
+	for subSys, tgtKV := range other {
+		if _, ok := cp[subSys]; !ok {
+			cp[subSys] = make(map[string]KVS)
+		}
+		for tgt, kvs := range tgtKV {
+			ckvs, ok := cp[subSys][tgt]
+			if !ok {
+				cp[subSys][tgt] = kvs.Clone()
+				continue
+			}
+			for _, kv := range kvs {
+				if _, ok := ckvs.Lookup(kv.Key); ok && !overwrite {
+					continue
+				}
+				ckvs.Set(kv.Key, kv.Value)
+			}
+			cp[subSys][tgt] = ckvs
+		}
+	}
+	return cp
+}
+
+// SafeConfig - wraps Config behind a lock so that it can be shared
+// between goroutines, callers only ever receive copies of the
+// underlying config. Raw Config remains in use for single threaded
+// code paths such as loading the config at startup.
+type SafeConfig struct {
+	mu  sync.RWMutex
+	cfg Config
+}
+
+// NewSafeConfig - returns a SafeConfig initialized with a copy of c.
+func NewSafeConfig(c Config) *SafeConfig {
+	return &SafeConfig{cfg: c.Clone()}
+}
+
+// SetKVS - same as Config.SetKVS.
+func (s *SafeConfig) SetKVS(in string, defaultKVS map[string]KVS) (dynamic bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.SetKVS(in, defaultKVS)
+}
+
+// GetKVS - same as Config.GetKVS, returned targets do not share
+// memory with the wrapped config.
+func (s *SafeConfig) GetKVS(in string, defaultKVS map[string]KVS) (Targets, error) {
+	// GetKVS fills in defaults on the returned targets,
+	// work on a copy to avoid sharing memory.
+	return s.Clone().GetKVS(in, defaultKVS)
+}
+
+// DelKVS - same as Config.DelKVS.
+func (s *SafeConfig) DelKVS(in string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cfg.DelKVS(in)
+}
+
+// Clone - returns a copy of the wrapped config.
+func (s *SafeConfig) Clone() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cfg.Clone()
+}
+
+// ForEach - calls fn for every target of every sub-system in c, sorted by
+// sub-system and then by target. Stops at and returns the first error
+// returned by fn.
+func (c Config) ForEach(fn func(subSys, target string, kvs KVS) error) error {
+	subSystems := make([]string, 0, len(c))
+	for subSys := range c {
+		subSystems = append(subSystems, subSys)
+	}
+	sort.Strings(subSystems)
+	for _, subSys := range subSystems {
+		targets := make([]string, 0, len(c[subSys]))
+		for tgt := range c[subSys] {
+			targets = append(targets, tgt)
+		}
+		sort.Strings(targets)
+		for _, tgt := range targets {
+			if err := fn(subSys, tgt, c[subSys][tgt]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SubSystemsInUse - returns the sorted list of sub-systems which have
+// at least one target with a value different from its default. The
+// comment key is ignored.
+func (c Config) SubSystemsInUse() []string {
+	var subSystems []string
+	for subSys, tgtKVS := range c {
+		defKVS := DefaultKVS[subSys]
+	targets:
+		for _, kvs := range tgtKVS {
+			for _, kv := range kvs {
+				if kv.Key == Comment {
+					continue
+				}
+				if v, ok := defKVS.Lookup(kv.Key); !ok || v != kv.Value {
+					subSystems = append(subSystems, subSys)
+					break targets
+				}
+			}
+		}
+	}
+	sort.Strings(subSystems)
+	return subSystems
+}
+
 // GetSubSys - extracts subssystem info from given config string
 func GetSubSys(s string) (subSys string, inputs []string, tgt string, e error) {
 	tgt = Default
 	if len(s) == 0 {
-		return subSys, inputs, tgt, Errorf("input arguments cannot be empty")
+		return subSys, inputs, tgt, errorKindf(ErrEmptyInput, "input arguments cannot be empty")
 	}
 	inputs = strings.SplitN(s, KvSpaceSeparator, 2)
 
 	subSystemValue := strings.SplitN(inputs[0], SubSystemSeparator, 2)
 	subSys = subSystemValue[0]
 	if !SubSystems.Contains(subSys) {
-		return subSys, inputs, tgt, Errorf("unknown sub-system %s", s)
+		return subSys, inputs, tgt, errorKindf(ErrUnknownSubSys, "unknown sub-system %s", s)
 	}
 
 	if len(inputs) == 1 {
@@ -836,7 +1733,7 @@ func GetSubSys(s string) (subSys string, inputs []string, tgt string, e error) {
 	}
 
 	if SubSystemsSingleTargets.Contains(subSystemValue[0]) && len(subSystemValue) == 2 {
-		return subSys, inputs, tgt, Errorf("sub-system '%s' only supports single target", subSystemValue[0])
+		return subSys, inputs, tgt, errorKindf(ErrSingleTargetViolation, "sub-system '%s' only supports single target", subSystemValue[0])
 	}
 
 	if len(subSystemValue) == 2 {
@@ -846,42 +1743,439 @@ func GetSubSys(s string) (subSys string, inputs []string, tgt string, e error) {
 	return subSys, inputs, tgt, e
 }
 
+// ValueFromFilePrefix - values of sensitive keys with this prefix are read
+// from the file at the path that follows, e.g.
+// `secret_key=valueFrom:file:/run/secret`. The reference is stored as is,
+// the file is only read when the config is looked up.
+const ValueFromFilePrefix = "valueFrom:file:"
+
+// resolveValueFrom - returns the contents of the file referred to by a
+// `valueFrom:file:` value, trailing newlines are removed. The file must
+// be inside the directory set with EnvConfigValueFromDir. Other values
+// are returned as is. Errors never include the contents of the file.
+func resolveValueFrom(key, value string) (string, error) {
+	path := strings.TrimPrefix(value, ValueFromFilePrefix)
+	if path == value {
+		return value, nil
+	}
+	if path == "" {
+		return "", Errorf("key '%s', missing file path for %s", key, ValueFromFilePrefix)
+	}
+	dir := env.Get(EnvConfigValueFromDir, "")
+	if dir == "" {
+		return "", Errorf("key '%s', %s references are disabled, set %s to allow them", key, ValueFromFilePrefix, EnvConfigValueFromDir)
+	}
+	realPath, err := valueFromPath(dir, path)
+	if err != nil {
+		return "", Errorf("key '%s', unable to read value from file: %v", key, err)
+	}
+	data, err := os.ReadFile(realPath)
+	if err != nil {
+		return "", Errorf("key '%s', unable to read value from file: %v", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// valueFromPath - returns path with all symlinks resolved, or an error if
+// it is not an absolute path of a file inside dir.
+func valueFromPath(dir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("'%s' is not an absolute path", path)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(realDir, realPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' is outside of %s", path, EnvConfigValueFromDir)
+	}
+	return realPath, nil
+}
+
+// ResolveValueFrom - returns a copy of the config with the `valueFrom:file:`
+// references of sensitive keys of subSystems, or of all sub-systems when
+// none are passed, replaced by the contents of their files. It is meant
+// for lookups only and must never be saved. References that cannot be
+// resolved are kept as is and the first such error is returned.
+func (c Config) ResolveValueFrom(subSystems ...string) (Config, error) {
+	resolved := c.Clone()
+	var firstErr error
+	for subSys, tgts := range resolved {
+		if len(subSystems) > 0 && !set.CreateStringSet(subSystems...).Contains(subSys) {
+			continue
+		}
+		sensitive := SensitiveKeys(subSys)
+		if len(sensitive) == 0 {
+			continue
+		}
+		for tgt, kvs := range tgts {
+			for i, kv := range kvs {
+				if !strings.HasPrefix(kv.Value, ValueFromFilePrefix) || !isSensitiveKey(sensitive, kv.Key) {
+					continue
+				}
+				value, err := resolveValueFrom(kv.Key, kv.Value)
+				if err != nil {
+					if firstErr == nil {
+						firstErr = Errorf("sub-system '%s' target '%s', %v", subSys, tgt, err)
+					}
+					continue
+				}
+				kvs[i].Value = value
+			}
+		}
+	}
+	return resolved, firstErr
+}
+
+// isSensitiveKey - returns true if key is one of sensitive.
+func isSensitiveKey(sensitive []string, key string) bool {
+	for _, k := range sensitive {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// changeHooks - hooks registered per sub-system with RegisterChangeHook.
+var changeHooks = struct {
+	sync.RWMutex
+	hooks map[string][]func(old, new KVS)
+}{hooks: map[string][]func(old, new KVS){}}
+
+// RegisterChangeHook - registers fn to be called whenever a value of
+// a target of subSys changes in the applied server config, see
+// NotifyChanges. old and new are copies of the target before and
+// after the change.
+func RegisterChangeHook(subSys string, fn func(old, new KVS)) {
+	changeHooks.Lock()
+	defer changeHooks.Unlock()
+	changeHooks.hooks[subSys] = append(changeHooks.hooks[subSys], fn)
+}
+
+// lastModified - modification times of the targets of the applied server
+// config, kept in memory only, so that the serialized config is not
+// affected.
+var lastModified = struct {
+	sync.RWMutex
+	times map[string]time.Time
+}{times: map[string]time.Time{}}
+
+func lastModifiedKey(subSys, target string) string {
+	if target == "" {
+		target = Default
+	}
+	return subSys + SubSystemSeparator + target
+}
+
+// TouchAppliedTarget - records the current time as the modification
+// time of the sub-system target of the applied server config. The times
+// are tracked for the lifetime of the process and updated by
+// NotifyChanges.
+func TouchAppliedTarget(subSys, target string) {
+	lastModified.Lock()
+	defer lastModified.Unlock()
+	lastModified.times[lastModifiedKey(subSys, target)] = time.Now()
+}
+
+// AppliedTargetModTime - returns the time the sub-system target was last
+// changed in the applied server config or touched, zero if it was not
+// changed since the process started.
+func AppliedTargetModTime(subSys, target string) time.Time {
+	lastModified.RLock()
+	defer lastModified.RUnlock()
+	return lastModified.times[lastModifiedKey(subSys, target)]
+}
+
+// kvsChange - a change to the stored KVS of a sub-system target.
+type kvsChange struct {
+	subSys, target string
+	old, new       KVS
+}
+
+// newKVSChange - returns the change from old to new, nil if no value
+// differs. Keys missing in old are compared with their default values,
+// so that backfilling defaults is not a change.
+func newKVSChange(subSys, target string, old, new, defaultKVS KVS) *kvsChange {
+	for _, kv := range new {
+		v, ok := old.Lookup(kv.Key)
+		if !ok {
+			v = defaultKVS.Get(kv.Key)
+		}
+		if v != kv.Value {
+			return &kvsChange{subSys: subSys, target: target, old: old.Clone(), new: new.Clone()}
+		}
+	}
+	return nil
+}
+
+// notify - records the modification time of the changed target and runs
+// the hooks registered for its sub-system, must not be called with any
+// lock held.
+func (ch *kvsChange) notify() {
+	if ch == nil {
+		return
+	}
+	TouchAppliedTarget(ch.subSys, ch.target)
+
+	changeHooks.RLock()
+	hooks := append([]func(old, new KVS){}, changeHooks.hooks[ch.subSys]...)
+	changeHooks.RUnlock()
+
+	for _, fn := range hooks {
+		fn(ch.old.Clone(), ch.new.Clone())
+	}
+}
+
+// NotifyChanges - runs the change hooks of subSys for every target whose
+// values differ between old and new, the targets of subSys before and
+// after a config change was saved and applied. Targets missing on either
+// side are compared with defaultKVS, must not be called with any lock
+// held.
+func NotifyChanges(subSys string, old, new map[string]KVS, defaultKVS KVS) {
+	targets := set.NewStringSet()
+	for tgt := range old {
+		targets.Add(tgt)
+	}
+	for tgt := range new {
+		targets.Add(tgt)
+	}
+	for _, tgt := range targets.ToSlice() {
+		oldKVS, ok := old[tgt]
+		if !ok {
+			oldKVS = defaultKVS
+		}
+		newKVS, ok := new[tgt]
+		if !ok {
+			newKVS = defaultKVS
+		}
+		newKVSChange(subSys, tgt, oldKVS, newKVS, defaultKVS).notify()
+	}
+}
+
+// SetKVSOpts - options to control how SetKVSWithOpts stores keys.
+type SetKVSOpts struct {
+	// PreserveOrder stores keys in the order they appear in the
+	// input, any other keys follow in their existing order.
+	PreserveOrder bool
+
+	// CommentInPlace does not move the comment key to the end,
+	// only applies along with PreserveOrder.
+	CommentInPlace bool
+}
+
 // SetKVS - set specific key values per sub-system.
 func (c Config) SetKVS(s string, defaultKVS map[string]KVS) (dynamic bool, err error) {
+	return c.SetKVSWithOpts(s, defaultKVS, SetKVSOpts{})
+}
+
+// SetKVSWithOpts - same as SetKVS, opts control the order of the
+// stored keys.
+func (c Config) SetKVSWithOpts(s string, defaultKVS map[string]KVS, opts SetKVSOpts) (dynamic bool, err error) {
 	subSys, inputs, tgt, err := GetSubSys(s)
 	if err != nil {
 		return false, err
 	}
 
-	dynamic = SubSystemsDynamic.Contains(subSys)
-
 	fields := madmin.KvFields(inputs[1], defaultKVS[subSys].Keys())
 	if len(fields) == 0 {
 		return false, Errorf("sub-system '%s' cannot have empty keys", subSys)
 	}
 
-	kvs := KVS{}
-	var prevK string
-	for _, v := range fields {
-		kv := strings.SplitN(v, KvSeparator, 2)
-		if len(kv) == 0 {
-			continue
+	kvs, err := reassembleKVFields(fields)
+	if err != nil {
+		return false, err
+	}
+	return c.applyKVS(subSys, tgt, kvs, defaultKVS, opts)
+}
+
+// reassembleKVFields - converts fields as returned by madmin.KvFields into
+// key value pairs. madmin.KvFields splits at the first occurrence of every
+// key, also within values, so the fields are joined and split again at the
+// keys found. A value quoted with single or double quotes may contain
+// spaces, '=' and other keys, it ends at the matching quote followed by
+// the next key. An unquoted value spans up to the next key. Anything from
+// a '#' following a value is a comment and ignored.
+func reassembleKVFields(fields []string) (KVS, error) {
+	keys := set.NewStringSet()
+	for _, field := range fields {
+		kv := strings.SplitN(field, KvSeparator, 2)
+		key := kv[0]
+		if len(kv) != 2 || key == "" {
+			return nil, Errorf("key '%s', cannot have empty value", field)
 		}
-		if len(kv) == 1 && prevK != "" {
-			value := strings.Join([]string{
-				kvs.Get(prevK),
-				madmin.SanitizeValue(kv[0]),
-			}, KvSpaceSeparator)
-			kvs.Set(prevK, value)
-			continue
+		keys.Add(key)
+	}
+	s := strings.Join(fields, KvSpaceSeparator)
+
+	// boundary - returns true if only spaces separate position i
+	// from the next key, a comment or the end of input.
+	boundary := func(i int) bool {
+		rest := strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+		if rest == "" || strings.HasPrefix(rest, KvComment) {
+			return true
 		}
+		kv := strings.SplitN(rest, KvSeparator, 2)
+		return len(kv) == 2 && keys.Contains(kv[0])
+	}
+	isSpace := func(i int) bool {
+		return unicode.IsSpace(rune(s[i]))
+	}
+
+	kvs := KVS{}
+	for i := 0; i < len(s); {
+		rest := strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+		if rest == "" || strings.HasPrefix(rest, KvComment) {
+			break
+		}
+		i = len(s) - len(rest)
+		kv := strings.SplitN(rest, KvSeparator, 2)
+		key, value := kv[0], ""
 		if len(kv) == 2 {
-			prevK = kv[0]
-			kvs.Set(prevK, madmin.SanitizeValue(kv[1]))
+			value = kv[1]
+		}
+		start := i + len(key) + len(KvSeparator)
+
+		end := -1
+		if value != "" && (value[0] == KvDoubleQuote[0] || value[0] == KvSingleQuote[0]) {
+			for j := start + 1; j < len(s); j++ {
+				// Escaped characters never end a double quoted value.
+				if value[0] == KvDoubleQuote[0] && s[j] == KvEscape[0] {
+					j++
+					continue
+				}
+				if s[j] == value[0] && (j+1 == len(s) || isSpace(j+1) && boundary(j+1)) {
+					end = j
+					break
+				}
+			}
+		}
+		if end >= 0 {
+			if value[0] == KvDoubleQuote[0] {
+				kvs.Set(key, unquoteValue(s[start+1:end]))
+			} else {
+				kvs.Set(key, s[start+1:end])
+			}
+			i = end + 1
 			continue
 		}
-		return false, Errorf("key '%s', cannot have empty value", kv[0])
+
+		// Unquoted or unterminated value.
+		end = start
+		for end < len(s) && !(isSpace(end) && boundary(end)) {
+			end++
+		}
+		kvs.Set(key, madmin.SanitizeValue(s[start:end]))
+		i = end
 	}
+	return kvs, nil
+}
+
+// SetFromMap - same as SetKVS but takes the key/value pairs of the
+// sub-system target from kv instead of the text format. An empty target
+// refers to the default target. Keys must be valid for the sub-system and
+// values are sanitized like unquoted values of the text format.
+func (c Config) SetFromMap(subSys, target string, kv map[string]string, defaultKVS map[string]KVS) (dynamic bool, err error) {
+	if !SubSystems.Contains(subSys) {
+		return false, errorKindf(ErrUnknownSubSys, "unknown sub-system %s", subSys)
+	}
+	if target == "" {
+		target = Default
+	}
+	if target != Default && SubSystemsSingleTargets.Contains(subSys) {
+		return false, errorKindf(ErrSingleTargetViolation, "sub-system '%s' only supports single target", subSys)
+	}
+	if len(kv) == 0 {
+		return false, Errorf("sub-system '%s' cannot have empty keys", subSys)
+	}
+
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make(KVS, 0, len(keys))
+	for _, k := range keys {
+		kvs.Set(k, madmin.SanitizeValue(kv[k]))
+	}
+	if err = CheckValidKeys(subSys, kvs, defaultKVS[subSys]); err != nil {
+		return false, err
+	}
+	return c.applyKVS(subSys, target, kvs, defaultKVS, SetKVSOpts{})
+}
+
+// checkTargetLimit - returns an error if a new target cannot be added
+// to subSys which has count named targets.
+func checkTargetLimit(subSys string, count int) error {
+	maxTargets := DefaultMaxTargets
+	if v := env.Get(EnvConfigMaxTargets, ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Errorf("invalid value '%s' for %s, must be a positive integer", v, EnvConfigMaxTargets)
+		}
+		maxTargets = n
+	}
+	if count >= maxTargets {
+		return errorKindf(ErrTooManyTargets, "sub-system '%s' cannot have more than %d targets, the limit can be changed with %s",
+			subSys, maxTargets, EnvConfigMaxTargets)
+	}
+	return nil
+}
+
+// checkNotifyAllowed - returns an error if the notification sub-system
+// subSys is not part of the allowlist of this deployment.
+func checkNotifyAllowed(subSys string) error {
+	v := env.Get(EnvConfigAllowedNotify, "")
+	if v == "" {
+		return nil
+	}
+	allowed := set.NewStringSet()
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if !NotifySubSystems.Contains(s) {
+			return Errorf("invalid value '%s' for %s, '%s' is not a notification sub-system", v, EnvConfigAllowedNotify, s)
+		}
+		allowed.Add(s)
+	}
+	if !allowed.Contains(subSys) {
+		return errorKindf(ErrNotifySubSysNotAllowed, "sub-system '%s' is not allowed in this deployment, allowed notification sub-systems are %s",
+			subSys, strings.Join(allowed.ToSlice(), ","))
+	}
+	return nil
+}
+
+// CheckNotifyAllowed - returns an error if the notification sub-system
+// subSys has any enabled target, configured in kvs or in the environment,
+// and is not allowed as per MINIO_CONFIG_ALLOWED_NOTIFY.
+func CheckNotifyAllowed(subSys string, kvs map[string]KVS) error {
+	err := checkNotifyAllowed(subSys)
+	if !errors.Is(err, ErrNotifySubSysNotAllowed) {
+		return err
+	}
+	enableEnv := EnvPrefix + strings.ToUpper(subSys) + EnvWordDelimiter + strings.ToUpper(Enable)
+	for tgt, kv := range Merge(kvs, enableEnv, nil) {
+		targetEnableEnv := enableEnv
+		if tgt != Default {
+			targetEnableEnv += EnvWordDelimiter + tgt
+		}
+		if enabled, perr := ParseBool(env.Get(targetEnableEnv, kv.Get(Enable))); perr == nil && enabled {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyKVS - validates kvs against defaultKVS and the help of subSys,
+// and stores them merged with the current values of the target.
+func (c Config) applyKVS(subSys, tgt string, kvs KVS, defaultKVS map[string]KVS, opts SetKVSOpts) (dynamic bool, err error) {
+	dynamic = IsDynamic(subSys)
+
+	inputKVS := kvs.Clone()
 
 	_, ok := kvs.Lookup(Enable)
 	// Check if state is required
@@ -891,8 +2185,19 @@ func (c Config) SetKVS(s string, defaultKVS map[string]KVS) (dynamic bool, err e
 		kvs.Set(Enable, EnableOn)
 	}
 
+	if NotifySubSystems.Contains(subSys) {
+		if err = checkNotifyAllowed(subSys); err != nil {
+			return false, err
+		}
+	}
+
 	var currKVS KVS
 	ck, ok := c[subSys][tgt]
+	if !ok && tgt != Default && !SubSystemsSingleTargets.Contains(subSys) {
+		if err = checkTargetLimit(subSys, c.CountTargets(subSys)); err != nil {
+			return false, err
+		}
+	}
 	if !ok {
 		currKVS = defaultKVS[subSys].Clone()
 	} else {
@@ -917,6 +2222,10 @@ func (c Config) SetKVS(s string, defaultKVS map[string]KVS) (dynamic bool, err e
 		currKVS.Set(Comment, v)
 	}
 
+	if opts.PreserveOrder {
+		currKVS = reorderKVS(currKVS, inputKVS, !opts.CommentInPlace)
+	}
+
 	hkvs := HelpSubSysMap[subSys]
 	for _, hkv := range hkvs {
 		var enabled bool
@@ -937,10 +2246,79 @@ func (c Config) SetKVS(s string, defaultKVS map[string]KVS) (dynamic bool, err e
 				hkv.Key, subSys, subSys)
 		}
 	}
+	if _, ok := c[subSys]; !ok {
+		c[subSys] = map[string]KVS{}
+	}
 	c[subSys][tgt] = currKVS
 	return dynamic, nil
 }
 
+// reorderKVS - returns kvs with the keys present in order first, followed
+// by the remaining keys in their existing order. The comment key is moved
+// to the end if commentLast is set.
+func reorderKVS(kvs KVS, order KVS, commentLast bool) KVS {
+	nkvs := make(KVS, 0, len(kvs))
+	for _, okv := range order {
+		if commentLast && okv.Key == Comment {
+			continue
+		}
+		if v, ok := kvs.Lookup(okv.Key); ok {
+			nkvs.Set(okv.Key, v)
+		}
+	}
+	for _, kv := range kvs {
+		if commentLast && kv.Key == Comment {
+			continue
+		}
+		if _, ok := nkvs.Lookup(kv.Key); !ok {
+			nkvs = append(nkvs, kv)
+		}
+	}
+	if commentLast {
+		if v, ok := kvs.Lookup(Comment); ok {
+			nkvs.Set(Comment, v)
+		}
+	}
+	return nkvs
+}
+
+// SetKVSMulti - applies all lines as with SetKVS, either all lines are
+// applied or none when any of them is invalid. Returns whether all the
+// applied sub-systems are dynamic.
+func (c Config) SetKVSMulti(lines []string, defaultKVS map[string]KVS) (dynOnly bool, err error) {
+	type subSysTarget struct {
+		subSys, target string
+	}
+
+	// Validate and apply on a copy first, so that either
+	// all lines are committed or none.
+	cp := c.Clone()
+	var applied []subSysTarget
+	dynOnly = true
+	for _, line := range lines {
+		// Skip any empty lines, or comment like characters
+		if line == "" || strings.HasPrefix(line, KvComment) {
+			continue
+		}
+		dynamic, err := cp.SetKVS(line, defaultKVS)
+		if err != nil {
+			return false, err
+		}
+		dynOnly = dynOnly && dynamic
+		subSys, _, tgt, _ := GetSubSys(line)
+		applied = append(applied, subSysTarget{subSys, tgt})
+	}
+
+	// All lines are valid, commit them.
+	for _, st := range applied {
+		if _, ok := c[st.subSys]; !ok {
+			c[st.subSys] = map[string]KVS{}
+		}
+		c[st.subSys][st.target] = cp[st.subSys][st.target]
+	}
+	return dynOnly, nil
+}
+
 // CheckValidKeys - checks if the config parameters for the given subsystem and
 // target are valid. It checks both the configuration store as well as
 // environment variables.
@@ -972,6 +2350,21 @@ func (c Config) CheckValidKeys(subSys string, deprecatedKeys []string) error {
 
 	isSingleTarget := SubSystemsSingleTargets.Contains(subSys)
 	if isSingleTarget && len(candidates) > 0 {
+		// Env vars which look like they configure a named target
+		// get a more helpful error message.
+		targetEnvVars := candidates.FuncMatch(func(envVar, _ string) bool {
+			for _, param := range validKeys {
+				pEnvName := getEnvVarName(subSys, Default, param) + Default
+				if len(envVar) > len(pEnvName) && strings.HasPrefix(envVar, pEnvName) {
+					return true
+				}
+			}
+			return false
+		}, "")
+		if !targetEnvVars.IsEmpty() {
+			return fmt.Errorf("sub-system '%s' only supports the default target, remove the target from the following environment variables: %s",
+				subSys, strings.Join(targetEnvVars.ToSlice(), ", "))
+		}
 		return fmt.Errorf("The following environment variables are unknown: %s",
 			strings.Join(candidates.ToSlice(), ", "))
 	}
@@ -1053,6 +2446,69 @@ func (c Config) GetAvailableTargets(subSys string) ([]string, error) {
 	return s.ToSlice(), nil
 }
 
+// CountTargets - returns the number of targets configured in the config
+// store for a sub-system, the default target is only counted when it is
+// explicitly enabled. Single target sub-systems always return 1.
+func (c Config) CountTargets(subSys string) int {
+	if SubSystemsSingleTargets.Contains(subSys) {
+		return 1
+	}
+	count := 0
+	for tgt, kvs := range c[subSys] {
+		if tgt != Default {
+			count++
+			continue
+		}
+		if enabled, err := ParseBool(kvs.Get(Enable)); err == nil && enabled {
+			count++
+		}
+	}
+	return count
+}
+
+// AllTargets - returns the named targets of all sub-systems, prefixed
+// with their sub-system and merged with the defaults. Single target
+// sub-systems are returned with their default target. Targets are
+// sorted by sub-system and name.
+func (c Config) AllTargets() []Target {
+	targets := []Target{}
+	for _, subSys := range SubSystems.ToSlice() {
+		if SubSystemsSingleTargets.Contains(subSys) {
+			targets = append(targets, Target{
+				SubSystem: subSys,
+				KVS:       mergeDefaults(subSys, c[subSys][Default]),
+			})
+			continue
+		}
+		names := make([]string, 0, len(c[subSys]))
+		for tgt := range c[subSys] {
+			if tgt != Default {
+				names = append(names, tgt)
+			}
+		}
+		sort.Strings(names)
+		for _, tgt := range names {
+			targets = append(targets, Target{
+				SubSystem: subSys + SubSystemSeparator + tgt,
+				KVS:       mergeDefaults(subSys, c[subSys][tgt]),
+			})
+		}
+	}
+	return targets
+}
+
+// mergeDefaults - returns a copy of kvs with missing keys added from
+// the defaults of subSys.
+func mergeDefaults(subSys string, kvs KVS) KVS {
+	merged := kvs.Clone()
+	for _, kv := range DefaultKVS[subSys] {
+		if _, ok := merged.Lookup(kv.Key); !ok {
+			merged = append(merged, kv)
+		}
+	}
+	return merged
+}
+
 func getEnvVarName(subSys, target, param string) string {
 	if target == Default {
 		return fmt.Sprintf("%s%s_%s", EnvPrefix, strings.ToUpper(subSys), strings.ToUpper(param))
@@ -1061,6 +2517,52 @@ func getEnvVarName(subSys, target, param string) string {
 	return fmt.Sprintf("%s%s_%s_%s", EnvPrefix, strings.ToUpper(subSys), strings.ToUpper(param), target)
 }
 
+// parseEnvVarName - reverse of getEnvVarName, parses an environment
+// variable name of the form MINIO_<SUBSYS>_<PARAM>[_<TARGET>] into its
+// components. Sub-systems and keys are matched against the registered
+// defaults and help, longest match first, since both may contain the
+// delimiter. Target names are returned as is.
+func parseEnvVarName(name string) (subSys, target, param string, ok bool) {
+	rest := strings.TrimPrefix(name, EnvPrefix)
+	if rest == name {
+		return "", "", "", false
+	}
+
+	subSystems := SubSystems.ToSlice()
+	sort.Slice(subSystems, func(i, j int) bool {
+		return len(subSystems[i]) > len(subSystems[j])
+	})
+	for _, subSys = range subSystems {
+		paramsPart := strings.TrimPrefix(rest, strings.ToUpper(subSys)+EnvWordDelimiter)
+		if paramsPart == rest {
+			continue
+		}
+
+		params := set.NewStringSet()
+		for _, kv := range DefaultKVS[subSys] {
+			params.Add(kv.Key)
+		}
+		for _, key := range HelpSubSysMap[subSys].keys() {
+			params.Add(key)
+		}
+		keys := params.ToSlice()
+		sort.Slice(keys, func(i, j int) bool {
+			return len(keys[i]) > len(keys[j])
+		})
+		for _, param = range keys {
+			target, ok = parseEnvTarget(paramsPart, strings.ToUpper(param))
+			if !ok {
+				continue
+			}
+			if target != Default && SubSystemsSingleTargets.Contains(subSys) {
+				continue
+			}
+			return subSys, target, param, true
+		}
+	}
+	return "", "", "", false
+}
+
 var resolvableSubsystems = set.CreateStringSet(IdentityOpenIDSubSys)
 
 // ValueSource represents the source of a config parameter value.
@@ -1093,6 +2595,12 @@ func (c Config) ResolveConfigParam(subSys, target, cfgParam string) (value strin
 		return
 	}
 
+	return c.resolveConfigParam(subSys, target, cfgParam)
+}
+
+// resolveConfigParam - same as ResolveConfigParam but works for all
+// sub-systems.
+func (c Config) resolveConfigParam(subSys, target, cfgParam string) (value string, cs ValueSource) {
 	// Check if config param requested is valid.
 	defKVS, ok := DefaultKVS[subSys]
 	if !ok {
@@ -1134,3 +2642,176 @@ func (c Config) ResolveConfigParam(subSys, target, cfgParam string) (value strin
 	cs = ValueSourceDef
 	return
 }
+
+// ResolveTarget - returns the effective KVS of a single sub-system
+// target, each key takes its value from the environment, the config
+// store or the defaults in that order of precedence, as returned by
+// ResolveConfigParam. Keys without defaults, such as comment, are
+// returned as stored.
+func (c Config) ResolveTarget(subSys, target string) (KVS, error) {
+	if rnSubSys, ok := renamedSubsys[subSys]; ok {
+		subSys = rnSubSys
+	}
+	defKVS, ok := DefaultKVS[subSys]
+	if !ok {
+		return nil, errorKindf(ErrUnknownSubSys, "unknown sub-system %s", subSys)
+	}
+	if target == "" {
+		target = Default
+	}
+	if target != Default && SubSystemsSingleTargets.Contains(subSys) {
+		return nil, errorKindf(ErrSingleTargetViolation, "sub-system '%s' only supports single target", subSys)
+	}
+
+	kvs := make(KVS, 0, len(defKVS))
+	for _, kv := range defKVS {
+		value, _ := c.resolveConfigParam(subSys, target, kv.Key)
+		kvs = append(kvs, KV{Key: kv.Key, Value: value})
+	}
+	for _, kv := range c[subSys][target] {
+		if _, ok := kvs.Lookup(kv.Key); !ok {
+			kvs = append(kvs, kv)
+		}
+	}
+	return kvs, nil
+}
+
+// EffectiveConfig - returns the effective key values of every target
+// of subSys, as resolved by ResolveTarget, keyed by target name. Targets
+// only configured in the environment are included and sensitive values
+// are redacted. Returns nil for an unknown sub-system.
+func (c Config) EffectiveConfig(subSys string) map[string]map[string]string {
+	if rnSubSys, ok := renamedSubsys[subSys]; ok {
+		subSys = rnSubSys
+	}
+	if _, ok := DefaultKVS[subSys]; !ok {
+		return nil
+	}
+
+	targets := set.CreateStringSet(Default)
+	for tgt := range c[subSys] {
+		targets.Add(tgt)
+	}
+	for _, override := range c.EnvOverrides() {
+		if override.SubSys == subSys {
+			targets.Add(override.Target)
+		}
+	}
+	sensitive := set.CreateStringSet(SensitiveKeys(subSys)...)
+
+	effective := make(map[string]map[string]string, len(targets))
+	for tgt := range targets {
+		kvs, err := c.ResolveTarget(subSys, tgt)
+		if err != nil {
+			continue
+		}
+		values := make(map[string]string, len(kvs))
+		for _, kv := range kvs {
+			if sensitive.Contains(kv.Key) && kv.Value != "" {
+				kv.Value = redactedValue
+			}
+			values[kv.Key] = kv.Value
+		}
+		effective[tgt] = values
+	}
+	return effective
+}
+
+// KVSrc - a key value along with the source of its effective value.
+type KVSrc struct {
+	Key   string      `json:"key"`
+	Value string      `json:"value"`
+	Src   ValueSource `json:"src"`
+}
+
+// DetailedTarget - a sub-system target with the source of each value.
+type DetailedTarget struct {
+	SubSystem string
+	KVS       []KVSrc
+}
+
+// GetDetailedKVS - same as GetKVS but additionally reports whether each
+// value comes from the environment, the config store or the defaults.
+// Values overridden by the environment are reported with their
+// environment value.
+func (c Config) GetDetailedKVS(s string, defaultKVS map[string]KVS) ([]DetailedTarget, error) {
+	targets, err := c.GetKVS(s, defaultKVS)
+	if err != nil {
+		return nil, err
+	}
+	dtargets := make([]DetailedTarget, 0, len(targets))
+	for _, target := range targets {
+		subSys, tgt := target.SubSystem, Default
+		if subSysTgt := strings.SplitN(target.SubSystem, SubSystemSeparator, 2); len(subSysTgt) == 2 {
+			subSys, tgt = subSysTgt[0], subSysTgt[1]
+		}
+		dt := DetailedTarget{
+			SubSystem: target.SubSystem,
+			KVS:       make([]KVSrc, 0, len(target.KVS)),
+		}
+		for _, kv := range target.KVS {
+			value, src := c.resolveConfigParam(subSys, tgt, kv.Key)
+			if src == ValueSourceAbsent {
+				// Keys without defaults such as comment.
+				value = kv.Value
+				if _, ok := c[subSys][tgt].Lookup(kv.Key); ok {
+					src = ValueSourceCfg
+				}
+			}
+			dt.KVS = append(dt.KVS, KVSrc{Key: kv.Key, Value: value, Src: src})
+		}
+		dtargets = append(dtargets, dt)
+	}
+	return dtargets, nil
+}
+
+// EnvOverride - a config parameter whose effective value is set by an
+// environment variable.
+type EnvOverride struct {
+	SubSys string `json:"subSys"`
+	Target string `json:"target"`
+	Key    string `json:"key"`
+	EnvVar string `json:"envVar"`
+	Value  string `json:"value"`
+}
+
+// EnvOverrides - lists the config parameters currently overridden by
+// environment variables, sorted by sub-system, target and key. Values of
+// sensitive parameters are redacted.
+func (c Config) EnvOverrides() []EnvOverride {
+	overrides := []EnvOverride{}
+	for _, kv := range os.Environ() {
+		name := strings.SplitN(kv, "=", 2)[0]
+		subSys, tgt, key, ok := parseEnvVarName(name)
+		if !ok || getEnvVarName(subSys, tgt, key) != name {
+			continue
+		}
+		value, src := c.resolveConfigParam(subSys, tgt, key)
+		if src != ValueSourceEnv {
+			continue
+		}
+		for _, sensitive := range SensitiveKeys(subSys) {
+			if sensitive == key {
+				value = redactedValue
+				break
+			}
+		}
+		overrides = append(overrides, EnvOverride{
+			SubSys: subSys,
+			Target: tgt,
+			Key:    key,
+			EnvVar: name,
+			Value:  value,
+		})
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].SubSys != overrides[j].SubSys {
+			return overrides[i].SubSys < overrides[j].SubSys
+		}
+		if overrides[i].Target != overrides[j].Target {
+			return overrides[i].Target < overrides[j].Target
+		}
+		return overrides[i].Key < overrides[j].Key
+	})
+	return overrides
+}
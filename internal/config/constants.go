@@ -37,6 +37,40 @@ const (
 	EnvRootUserFile     = "MINIO_ROOT_USER_FILE"
 	EnvRootPasswordFile = "MINIO_ROOT_PASSWORD_FILE"
 
+	// EnvCredentialsStrict refuses to fall back to the default
+	// credentials when none are configured.
+	EnvCredentialsStrict = "MINIO_CREDENTIALS_STRICT"
+
+	// EnvCredentialsValidateStrength rejects weak credentials, minimum
+	// key lengths beyond the ones required by S3 can be set with the
+	// following ENVs.
+	EnvCredentialsValidateStrength   = "MINIO_CREDENTIALS_VALIDATE_STRENGTH"
+	EnvCredentialsMinAccessKeyLength = "MINIO_CREDENTIALS_MIN_ACCESS_KEY_LENGTH"
+	EnvCredentialsMinSecretKeyLength = "MINIO_CREDENTIALS_MIN_SECRET_KEY_LENGTH"
+
+	// EnvConfigMaxTargets changes the maximum number of targets per
+	// sub-system, defaults to DefaultMaxTargets.
+	EnvConfigMaxTargets = "MINIO_CONFIG_MAX_TARGETS"
+
+	// EnvConfigAllowedNotify restricts the notification sub-systems
+	// that can be configured to a comma separated list, e.g.
+	// "notify_webhook,notify_kafka", all are allowed when empty.
+	EnvConfigAllowedNotify = "MINIO_CONFIG_ALLOWED_NOTIFY"
+
+	// EnvConfigValueFromDir is the directory `valueFrom:file:` references
+	// of sensitive keys may point into, references are refused when unset.
+	EnvConfigValueFromDir = "MINIO_CONFIG_VALUE_FROM_DIR"
+
+	// EnvConfigProbeEndpoints checks that webhook endpoints are
+	// reachable when their config is set, failures are logged as
+	// warnings unless EnvConfigProbeEndpointsStrict is enabled.
+	EnvConfigProbeEndpoints       = "MINIO_CONFIG_PROBE_ENDPOINTS"
+	EnvConfigProbeEndpointsStrict = "MINIO_CONFIG_PROBE_ENDPOINTS_STRICT"
+
+	// EnvProfilerDir is where the profilers writing to files create
+	// their temporary directories.
+	EnvProfilerDir = "MINIO_PROFILER_DIR"
+
 	// Set all config environment variables from 'config.env'
 	// if necessary. Overrides all previous settings and also
 	// overrides all environment values passed from
@@ -54,6 +88,11 @@ const (
 	EnvSiteName   = "MINIO_SITE_NAME"
 	EnvSiteRegion = "MINIO_SITE_REGION"
 
+	// EnvSiteAutoName derives the site name from the hostname when no
+	// site name is configured, in a distributed setup from the host of
+	// the first endpoint.
+	EnvSiteAutoName = "MINIO_SITE_AUTO_NAME"
+
 	EnvMinIOSubnetLicense = "MINIO_SUBNET_LICENSE" // Deprecated Dec 2021
 	EnvMinIOSubnetAPIKey  = "MINIO_SUBNET_API_KEY"
 	EnvMinIOSubnetProxy   = "MINIO_SUBNET_PROXY"
@@ -67,6 +106,10 @@ const (
 
 	EnvUpdate = "MINIO_UPDATE"
 
+	// EnvTLSAllowInsecureSkipVerify allows outbound connections to skip
+	// verification of the server certificate where requested.
+	EnvTLSAllowInsecureSkipVerify = "MINIO_TLS_ALLOW_INSECURE_SKIP_VERIFY"
+
 	EnvKMSSecretKey      = "MINIO_KMS_SECRET_KEY"
 	EnvKMSSecretKeyFile  = "MINIO_KMS_SECRET_KEY_FILE"
 	EnvKESEndpoint       = "MINIO_KMS_KES_ENDPOINT"
@@ -75,6 +118,15 @@ const (
 	EnvKESClientPassword = "MINIO_KMS_KES_KEY_PASSWORD"
 	EnvKESClientCert     = "MINIO_KMS_KES_CERT_FILE"
 	EnvKESServerCA       = "MINIO_KMS_KES_CAPATH"
+	EnvKMSAutoEncryption = "MINIO_KMS_AUTO_ENCRYPTION"
+
+	EnvCompressState           = "MINIO_COMPRESS_ENABLE"
+	EnvCompressAllowEncryption = "MINIO_COMPRESS_ALLOW_ENCRYPTION"
+	EnvCompress                = "MINIO_COMPRESS" // legacy
+
+	// CompressAllowEncrypted is the compression sub-system key
+	// allowing encrypted objects to be compressed.
+	CompressAllowEncrypted = "allow_encryption"
 
 	EnvEndpoints  = "MINIO_ENDPOINTS"   // legacy
 	EnvWorm       = "MINIO_WORM"        // legacy
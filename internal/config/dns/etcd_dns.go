@@ -22,13 +22,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/coredns/coredns/plugin/etcd/msg"
 	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/minio/internal/config"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -288,11 +288,9 @@ func NewCoreDNS(cfg clientv3.Config, setters ...EtcdOption) (Store, error) {
 
 	// strip ports off of domainIPs
 	domainIPsWithoutPorts := args.domainIPs.ApplyFunc(func(ip string) string {
-		host, _, err := net.SplitHostPort(ip)
+		host, _, err := config.SplitHostPort(ip)
 		if err != nil {
-			if strings.Contains(err.Error(), "missing port in address") {
-				host = ip
-			}
+			return ""
 		}
 		return host
 	})
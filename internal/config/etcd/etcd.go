@@ -20,10 +20,14 @@ package etcd
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"net"
 	"strings"
 	"time"
 
+	"github.com/minio/minio/internal/color"
 	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger"
 	"github.com/minio/pkg/env"
 	xnet "github.com/minio/pkg/net"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -35,6 +39,10 @@ const (
 	// Default values used while communicating with etcd.
 	defaultDialTimeout   = 5 * time.Second
 	defaultDialKeepAlive = 30 * time.Second
+
+	// Ports assumed for endpoints configured without a port.
+	defaultHTTPPort  = "2379"
+	defaultHTTPSPort = "2380"
 )
 
 // etcd environment values
@@ -101,8 +109,46 @@ func New(cfg Config) (*clientv3.Client, error) {
 	return cli, nil
 }
 
+// ValidateEtcdEndpoints - parses the comma separated etcd endpoints,
+// each must be an http or https URL with a host. Endpoints without a
+// port are deprecated, the default port of their scheme is assumed.
+// Returns the endpoints normalized to scheme://host:port.
+func ValidateEtcdEndpoints(raw string) ([]string, error) {
+	var endpoints []string
+	for _, endpoint := range strings.Split(raw, config.ValueSeparator) {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			return nil, config.Errorf("etcd endpoint cannot be empty: '%s'", raw)
+		}
+		u, err := xnet.ParseHTTPURL(endpoint)
+		if err != nil {
+			return nil, config.Errorf("invalid etcd endpoint '%s': %v", endpoint, err)
+		}
+		if u.Path != "" && u.Path != "/" {
+			return nil, config.Errorf("invalid etcd endpoint '%s': unexpected path '%s'", endpoint, u.Path)
+		}
+		if strings.HasSuffix(u.Host, ":") {
+			return nil, config.Errorf("invalid etcd endpoint '%s': empty port", endpoint)
+		}
+		host := u.Host
+		if u.Port() == "" {
+			port := defaultHTTPPort
+			if u.Scheme == "https" {
+				port = defaultHTTPSPort
+			}
+			host = net.JoinHostPort(u.Hostname(), port)
+			logger.Info(color.Yellow(fmt.Sprintf("WARNING: etcd endpoint '%s' without a port is deprecated, assuming '%s'", endpoint, u.Scheme+"://"+host)))
+		}
+		endpoints = append(endpoints, u.Scheme+"://"+host)
+	}
+	return endpoints, nil
+}
+
 func parseEndpoints(endpoints string) ([]string, bool, error) {
-	etcdEndpoints := strings.Split(endpoints, config.ValueSeparator)
+	etcdEndpoints, err := ValidateEtcdEndpoints(endpoints)
+	if err != nil {
+		return nil, false, err
+	}
 
 	var etcdSecure bool
 	for _, endpoint := range etcdEndpoints {
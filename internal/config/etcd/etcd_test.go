@@ -69,3 +69,46 @@ func TestParseEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateEtcdEndpoints(t *testing.T) {
+	testCases := []struct {
+		raw       string
+		endpoints []string
+		success   bool
+	}{
+		// Valid inputs
+		{
+			"https://etcd1:2379, https://etcd2:2379/,http://[::1]:2380",
+			[]string{"https://etcd1:2379", "https://etcd2:2379", "http://[::1]:2380"},
+			true,
+		},
+		{"HTTP://localhost:2379", []string{"http://localhost:2379"}, true},
+		// Deprecated, the default port of the scheme is assumed.
+		{"http://localhost", []string{"http://localhost:2379"}, true},
+		{"https://etcd1,https://[::1]", []string{"https://etcd1:2380", "https://[::1]:2380"}, true},
+
+		// Invalid inputs
+		{"", nil, false},
+		{"http://localhost:2379,", nil, false},
+		{"unix://localhost:2379", nil, false},
+		{"localhost:2379", nil, false},
+		{"https://localhost:", nil, false},
+		{"http://localhost:2379/v3", nil, false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.raw, func(t *testing.T) {
+			endpoints, err := ValidateEtcdEndpoints(testCase.raw)
+			if err != nil && testCase.success {
+				t.Errorf("expected to succeed but failed with %s", err)
+			}
+			if !testCase.success && err == nil {
+				t.Errorf("expected failure but succeeded instead with %s", endpoints)
+			}
+			if testCase.success && !reflect.DeepEqual(endpoints, testCase.endpoints) {
+				t.Errorf("expected %s, got %s", testCase.endpoints, endpoints)
+			}
+		})
+	}
+}
@@ -31,6 +31,10 @@ type HelpKV struct {
 
 	// Indicates if sub-sys supports multiple targets.
 	MultipleTargets bool `json:"multipleTargets"`
+
+	// Indicates if a change to this key only takes effect
+	// after a restart, even when the sub-sys is dynamic.
+	RestartRequired bool `json:"-"`
 }
 
 // HelpKVS - implement order of keys help messages.
@@ -46,6 +50,15 @@ func (hkvs HelpKVS) Lookup(key string) (HelpKV, bool) {
 	return HelpKV{}, false
 }
 
+// keys - returns all the keys in the help kvs.
+func (hkvs HelpKVS) keys() []string {
+	keys := make([]string, 0, len(hkvs))
+	for _, hkv := range hkvs {
+		keys = append(keys, hkv.Key)
+	}
+	return keys
+}
+
 // DefaultComment used across all sub-systems.
 const DefaultComment = "optionally add a comment to this setting"
 
@@ -29,6 +29,7 @@ import (
 	ldap "github.com/go-ldap/ldap/v3"
 	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/minio/internal/auth"
+	"github.com/minio/minio/internal/config"
 )
 
 func getGroups(conn *ldap.Conn, sreq *ldap.SearchRequest) ([]string, error) {
@@ -202,10 +203,13 @@ func (l *Config) Connect() (ldapConn *ldap.Conn, err error) {
 		return nil, errors.New("LDAP is not configured")
 	}
 
-	_, _, err = net.SplitHostPort(l.ServerAddr)
+	host, port, err := config.SplitHostPort(l.ServerAddr)
 	if err != nil {
+		return nil, err
+	}
+	if port == "" {
 		// User default LDAP port if none specified "636"
-		l.ServerAddr = net.JoinHostPort(l.ServerAddr, "636")
+		l.ServerAddr = net.JoinHostPort(host, "636")
 	}
 
 	if l.serverInsecure {
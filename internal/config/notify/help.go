@@ -85,6 +85,12 @@ var (
 			Type:        "string",
 			Sensitive:   true,
 		},
+		config.HelpKV{
+			Key:         target.WebhookCompress,
+			Description: `set to "on" to accept gzip compressed responses from the Webhook endpoint, defaults to "off"`,
+			Optional:    true,
+			Type:        "on|off",
+		},
 	}
 
 	HelpAMQP = config.HelpKVS{
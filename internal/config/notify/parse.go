@@ -120,6 +120,15 @@ func fetchSubSysTargets(ctx context.Context, cfg config.Config,
 	if err := checkValidNotificationKeysForSubSys(subSys, cfg[subSys]); err != nil {
 		return targetsOffline, err
 	}
+	if err := config.CheckNotifyAllowed(subSys, cfg[subSys]); err != nil {
+		if test {
+			return targetsOffline, err
+		}
+		// Skip the targets of a sub-system that is not allowed,
+		// without failing the other sub-systems.
+		logger.LogIf(ctx, err)
+		return targetsOffline, nil
+	}
 
 	switch subSys {
 	case config.NotifyAMQPSubSys:
@@ -1475,6 +1484,10 @@ var (
 			Key:   target.WebhookClientKey,
 			Value: "",
 		},
+		config.KV{
+			Key:   target.WebhookCompress,
+			Value: config.EnableOff,
+		},
 	}
 )
 
@@ -1529,6 +1542,15 @@ func GetNotifyWebhook(webhookKVS map[string]config.KVS, transport *http.Transpor
 			clientKeyEnv = clientKeyEnv + config.Default + k
 		}
 
+		compressEnv := target.EnvWebhookCompress
+		if k != config.Default {
+			compressEnv = compressEnv + config.Default + k
+		}
+		compress, err := config.ParseBool(env.Get(compressEnv, kv.GetWithDefault(target.WebhookCompress, DefaultWebhookKVS)))
+		if err != nil {
+			return nil, err
+		}
+
 		webhookArgs := target.WebhookArgs{
 			Enable:     enabled,
 			Endpoint:   *url,
@@ -1538,6 +1560,7 @@ func GetNotifyWebhook(webhookKVS map[string]config.KVS, transport *http.Transpor
 			QueueLimit: uint64(queueLimit),
 			ClientCert: env.Get(clientCertEnv, kv.Get(target.WebhookClientCert)),
 			ClientKey:  env.Get(clientKeyEnv, kv.Get(target.WebhookClientKey)),
+			Compress:   compress,
 		}
 		if err = webhookArgs.Validate(); err != nil {
 			return nil, err
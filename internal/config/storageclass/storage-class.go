@@ -160,6 +160,24 @@ func parseStorageClass(storageClassEnv string) (sc StorageClass, err error) {
 	}, nil
 }
 
+// ParseStorageClass - parses a storage class value of the form "EC:N"
+// and validates the parity N as a standard storage class parity of an
+// erasure set with setDriveCount drives, see validateParity.
+func ParseStorageClass(value string, setDriveCount int) (StorageClass, error) {
+	sc, err := parseStorageClass(value)
+	if err != nil {
+		return StorageClass{}, err
+	}
+	if sc.Parity < 0 {
+		return StorageClass{}, config.ErrStorageClassValue(nil).Msg(
+			fmt.Sprintf("Parity %d in %s should not be negative", sc.Parity, value))
+	}
+	if err = validateParity(sc.Parity, 0, setDriveCount); err != nil {
+		return StorageClass{}, config.ErrStorageClassValue(err)
+	}
+	return sc, nil
+}
+
 // ValidateParity validate standard storage class parity.
 func ValidateParity(ssParity, setDriveCount int) error {
 	// SS parity disks should be greater than or equal to minParityDisks.
@@ -262,14 +280,14 @@ func LookupConfig(kvs config.KVS, setDriveCount int) (cfg Config, err error) {
 	rrsc := env.Get(RRSEnv, kvs.Get(ClassRRS))
 	// Check for environment variables and parse into storageClass struct
 	if ssc != "" {
-		cfg.Standard, err = parseStorageClass(ssc)
+		cfg.Standard, err = ParseStorageClass(ssc, setDriveCount)
 		if err != nil {
 			return Config{}, err
 		}
 	}
 
 	if rrsc != "" {
-		cfg.RRS, err = parseStorageClass(rrsc)
+		cfg.RRS, err = ParseStorageClass(rrsc, setDriveCount)
 		if err != nil {
 			return Config{}, err
 		}
@@ -92,6 +92,37 @@ func TestParseStorageClass(t *testing.T) {
 	}
 }
 
+func TestParseStorageClassWithDriveCount(t *testing.T) {
+	tests := []struct {
+		value         string
+		setDriveCount int
+		wantSc        StorageClass
+		wantErr       bool
+	}{
+		{"EC:2", 16, StorageClass{Parity: 2}, false},
+		{"EC:8", 16, StorageClass{Parity: 8}, false},
+		{"EC:9", 16, StorageClass{}, true},
+		{"EC:8", 4, StorageClass{}, true},
+		{"EC:1", 16, StorageClass{}, true},
+		// Zero parity is accepted, as with validateParity.
+		{"EC:0", 16, StorageClass{Parity: 0}, false},
+		{"EC:-2", 16, StorageClass{}, true},
+		{"EC:", 16, StorageClass{}, true},
+		{"EC2", 16, StorageClass{}, true},
+		{"RS:2", 16, StorageClass{}, true},
+	}
+	for i, tt := range tests {
+		gotSc, err := ParseStorageClass(tt.value, tt.setDriveCount)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Test %d, %s: expected error %t, got %v", i+1, tt.value, tt.wantErr, err)
+			continue
+		}
+		if gotSc != tt.wantSc {
+			t.Errorf("Test %d, %s: expected %v, got %v", i+1, tt.value, tt.wantSc, gotSc)
+		}
+	}
+}
+
 func TestValidateParity(t *testing.T) {
 	tests := []struct {
 		rrsParity     int
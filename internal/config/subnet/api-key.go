@@ -18,6 +18,8 @@
 package subnet
 
 import (
+	"fmt"
+
 	"github.com/minio/minio/internal/config"
 	"github.com/minio/pkg/env"
 	xnet "github.com/minio/pkg/net"
@@ -51,6 +53,22 @@ type Config struct {
 	ProxyURL *xnet.URL `json:"proxy_url"`
 }
 
+// DeprecationWarnings - returns a warning if the deprecated license is
+// still set in kvs or in the environment, the config is not rejected
+// since the license keeps working until it is replaced by an api_key.
+func DeprecationWarnings(kvs config.KVS) []string {
+	var warnings []string
+	if kvs.Get(config.License) != "" {
+		warnings = append(warnings, fmt.Sprintf("key '%s' in '%s' is deprecated since Dec 2021, please use '%s' instead",
+			config.License, config.SubnetSubSys, config.APIKey))
+	}
+	if env.IsSet(config.EnvMinIOSubnetLicense) {
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated since Dec 2021, please use %s instead",
+			config.EnvMinIOSubnetLicense, config.EnvMinIOSubnetAPIKey))
+	}
+	return warnings
+}
+
 // LookupConfig - lookup config and override with valid environment settings if any.
 func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 	if err = config.CheckValidKeys(config.SubnetSubSys, kvs, DefaultKVS); err != nil {
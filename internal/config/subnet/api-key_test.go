@@ -0,0 +1,44 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package subnet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/internal/config"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	kvs := DefaultKVS.Clone()
+	kvs.Set(config.APIKey, "api-key")
+	if warnings := DeprecationWarnings(kvs); len(warnings) != 0 {
+		t.Fatalf("Expected no warnings without a license, got %v", warnings)
+	}
+
+	kvs.Set(config.License, "license-token")
+	warnings := DeprecationWarnings(kvs)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], config.License) || !strings.Contains(warnings[0], config.APIKey) {
+		t.Fatalf("Expected a deprecation warning for the license, got %v", warnings)
+	}
+
+	t.Setenv(config.EnvMinIOSubnetLicense, "license-token")
+	if warnings = DeprecationWarnings(kvs); len(warnings) != 2 {
+		t.Fatalf("Expected a deprecation warning for %s, got %v", config.EnvMinIOSubnetLicense, warnings)
+	}
+}
@@ -28,7 +28,7 @@ const (
 	// requires a valid KMS configuration and turns any non-SSE-C
 	// request into an SSE-S3 request.
 	// If present EnvAutoEncryption must be either "on" or "off".
-	EnvKMSAutoEncryption = "MINIO_KMS_AUTO_ENCRYPTION"
+	EnvKMSAutoEncryption = config.EnvKMSAutoEncryption
 )
 
 // LookupAutoEncryption returns true if and only if
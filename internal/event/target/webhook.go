@@ -47,6 +47,7 @@ const (
 	WebhookQueueLimit = "queue_limit"
 	WebhookClientCert = "client_cert"
 	WebhookClientKey  = "client_key"
+	WebhookCompress   = "compress"
 
 	EnvWebhookEnable     = "MINIO_NOTIFY_WEBHOOK_ENABLE"
 	EnvWebhookEndpoint   = "MINIO_NOTIFY_WEBHOOK_ENDPOINT"
@@ -55,6 +56,7 @@ const (
 	EnvWebhookQueueLimit = "MINIO_NOTIFY_WEBHOOK_QUEUE_LIMIT"
 	EnvWebhookClientCert = "MINIO_NOTIFY_WEBHOOK_CLIENT_CERT"
 	EnvWebhookClientKey  = "MINIO_NOTIFY_WEBHOOK_CLIENT_KEY"
+	EnvWebhookCompress   = "MINIO_NOTIFY_WEBHOOK_COMPRESS"
 )
 
 // WebhookArgs - Webhook target arguments.
@@ -67,6 +69,7 @@ type WebhookArgs struct {
 	QueueLimit uint64          `json:"queueLimit"`
 	ClientCert string          `json:"clientCert"`
 	ClientKey  string          `json:"clientKey"`
+	Compress   bool            `json:"compress"`
 }
 
 // Validate WebhookArgs fields
@@ -231,6 +234,18 @@ func (target *WebhookTarget) Close() error {
 	return nil
 }
 
+// webhookTransport - returns a copy of transport which transparently
+// decompresses gzip encoded responses if compress is set, object data
+// transports stay raw so transport itself is never changed.
+func webhookTransport(transport *http.Transport, compress bool) *http.Transport {
+	if !compress || !transport.DisableCompression {
+		return transport
+	}
+	transport = transport.Clone()
+	transport.DisableCompression = false
+	return transport
+}
+
 // NewWebhookTarget - creates new Webhook target.
 func NewWebhookTarget(ctx context.Context, id string, args WebhookArgs, loggerOnce func(ctx context.Context, err error, id interface{}, kind ...interface{}), transport *http.Transport, test bool) (*WebhookTarget, error) {
 	var store Store
@@ -240,6 +255,7 @@ func NewWebhookTarget(ctx context.Context, id string, args WebhookArgs, loggerOn
 		loggerOnce: loggerOnce,
 	}
 
+	transport = webhookTransport(transport, args.Compress)
 	if target.args.ClientCert != "" && target.args.ClientKey != "" {
 		manager, err := certs.NewManager(ctx, target.args.ClientCert, target.args.ClientKey, tls.LoadX509KeyPair)
 		if err != nil {
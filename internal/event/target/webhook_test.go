@@ -0,0 +1,76 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookTransportCompress(t *testing.T) {
+	const body = `{"status":"ok"}`
+
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	zw.Write([]byte(body))
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipped.Bytes())
+	}))
+	defer server.Close()
+
+	transport := &http.Transport{DisableCompression: true}
+	defer transport.CloseIdleConnections()
+
+	get := func(tr *http.Transport) []byte {
+		resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// Default transport returns the raw stream.
+	if tr := webhookTransport(transport, false); tr != transport {
+		t.Fatal("expected transport to be unchanged")
+	}
+	if b := get(transport); !bytes.Equal(b, gzipped.Bytes()) {
+		t.Fatalf("expected raw gzipped response, got %q", b)
+	}
+
+	// Compression enabled transport decodes the response.
+	tr := webhookTransport(transport, true)
+	defer tr.CloseIdleConnections()
+	if b := get(tr); string(b) != body {
+		t.Fatalf("expected decoded response %q, got %q", body, b)
+	}
+	if !transport.DisableCompression {
+		t.Fatal("expected shared transport to stay raw")
+	}
+}
@@ -30,6 +30,7 @@ const (
 	ContentLanguage    = "Content-Language"
 	ContentRange       = "Content-Range"
 	Connection         = "Connection"
+	Accept             = "Accept"
 	AcceptRanges       = "Accept-Ranges"
 	AmzBucketRegion    = "X-Amz-Bucket-Region"
 	ServerInfo         = "Server"
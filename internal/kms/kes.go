@@ -52,6 +52,12 @@ type Config struct {
 	// RootCAs is a set of root CA certificates
 	// to verify the KMS server TLS certificate.
 	RootCAs *x509.CertPool
+
+	// ClientSessionCache caches the TLS sessions
+	// to the KMS servers, a cache holding
+	// tlsClientSessionCacheSize sessions is used
+	// if nil.
+	ClientSessionCache tls.ClientSessionCache
 }
 
 // NewWithConfig returns a new KMS using the given
@@ -63,11 +69,15 @@ func NewWithConfig(config Config) (KMS, error) {
 	endpoints := make([]string, len(config.Endpoints)) // Copy => avoid being affect by any changes to the original slice
 	copy(endpoints, config.Endpoints)
 
+	sessionCache := config.ClientSessionCache
+	if sessionCache == nil {
+		sessionCache = tls.NewLRUClientSessionCache(tlsClientSessionCacheSize)
+	}
 	client := kes.NewClientWithConfig("", &tls.Config{
 		MinVersion:         tls.VersionTLS12,
 		Certificates:       []tls.Certificate{config.Certificate},
 		RootCAs:            config.RootCAs,
-		ClientSessionCache: tls.NewLRUClientSessionCache(tlsClientSessionCacheSize),
+		ClientSessionCache: sessionCache,
 	})
 	client.Endpoints = endpoints
 
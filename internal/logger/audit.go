@@ -24,6 +24,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/klauspost/compress/gzhttp"
@@ -234,7 +235,112 @@ func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqCl
 	// Send audit logs only to http targets.
 	for _, t := range auditTgts {
 		if err := t.Send(entry, string(All)); err != nil {
+			suppressed, ok := auditFailures.report(t.String(), err)
+			if !ok {
+				continue
+			}
+			if suppressed > 0 {
+				err = fmt.Errorf("%w (%d similar failures suppressed in the last %s)", err, suppressed, auditFailures.interval)
+			}
 			LogAlwaysIf(context.Background(), fmt.Errorf("event(%v) was not sent to Audit target (%v): %v", entry, t, err), All)
 		}
 	}
 }
+
+// auditFailureInterval - identical audit delivery failures are
+// logged at most once per interval.
+const auditFailureInterval = time.Minute
+
+// auditFailureLimiter - collapses repeated identical audit delivery
+// failures, so that an unavailable audit target does not flood the
+// logs with one entry per audited request. Suppressed failures are
+// summarized once per interval, even if no further failure occurs.
+type auditFailureLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	failures map[string]*auditFailure
+
+	// summarize is called with the number of failures suppressed
+	// for a target since its failure was last logged.
+	summarize func(target string, err error, suppressed int)
+	start     sync.Once
+}
+
+type auditFailure struct {
+	target     string
+	err        error
+	logged     time.Time
+	suppressed int
+}
+
+var auditFailures = newAuditFailureLimiter(auditFailureInterval, func(target string, err error, suppressed int) {
+	LogAlwaysIf(context.Background(), fmt.Errorf("%d events were not sent to Audit target (%v) in the last %s: %v",
+		suppressed, target, auditFailureInterval, err), All)
+})
+
+func newAuditFailureLimiter(interval time.Duration, summarize func(target string, err error, suppressed int)) *auditFailureLimiter {
+	return &auditFailureLimiter{
+		interval:  interval,
+		failures:  make(map[string]*auditFailure),
+		summarize: summarize,
+	}
+}
+
+// report - records a delivery failure of target, returns true if it
+// should be logged along with the number of identical failures
+// suppressed since it was last logged.
+func (l *auditFailureLimiter) report(target string, err error) (suppressed int, ok bool) {
+	l.start.Do(func() { go l.run() })
+
+	key := target + ": " + err.Error()
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, found := l.failures[key]
+	if found && now.Sub(f.logged) < l.interval {
+		f.suppressed++
+		return 0, false
+	}
+	if !found {
+		f = &auditFailure{target: target, err: err}
+		l.failures[key] = f
+	}
+	suppressed = f.suppressed
+	f.logged, f.suppressed = now, 0
+	return suppressed, true
+}
+
+// run - flushes the suppressed failures once per interval.
+func (l *auditFailureLimiter) run() {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.flush(time.Now())
+	}
+}
+
+// flush - summarizes the failures suppressed for at least an interval
+// and forgets the failures which have not been seen for a while.
+func (l *auditFailureLimiter) flush(now time.Time) {
+	var summaries []auditFailure
+
+	l.mu.Lock()
+	for k, f := range l.failures {
+		if now.Sub(f.logged) < l.interval {
+			continue
+		}
+		if f.suppressed == 0 {
+			delete(l.failures, k)
+			continue
+		}
+		summaries = append(summaries, *f)
+		f.logged, f.suppressed = now, 0
+	}
+	l.mu.Unlock()
+
+	for _, f := range summaries {
+		l.summarize(f.target, f.err, f.suppressed)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuditFailureLimiter(t *testing.T) {
+	l := newAuditFailureLimiter(time.Hour, func(string, error, int) {})
+	errUnavailable := errors.New("audit target unavailable")
+
+	// The first failure is logged, repeated ones are suppressed.
+	if suppressed, ok := l.report("http://audit", errUnavailable); !ok || suppressed != 0 {
+		t.Fatalf("Expected the first failure to be logged, got %v %d", ok, suppressed)
+	}
+	for i := 0; i < 10; i++ {
+		if _, ok := l.report("http://audit", errUnavailable); ok {
+			t.Fatalf("Expected failure %d to be suppressed", i+2)
+		}
+	}
+
+	// Different errors and targets are not collapsed together.
+	if _, ok := l.report("http://audit", errors.New("connection refused")); !ok {
+		t.Fatal("Expected a different error to be logged")
+	}
+	if _, ok := l.report("http://audit2", errUnavailable); !ok {
+		t.Fatal("Expected a different target to be logged")
+	}
+
+	// Once the interval elapsed the next failure is logged with the
+	// number of suppressed failures.
+	l.mu.Lock()
+	l.failures["http://audit: audit target unavailable"].logged = time.Now().Add(-time.Hour)
+	l.mu.Unlock()
+	if suppressed, ok := l.report("http://audit", errUnavailable); !ok || suppressed != 10 {
+		t.Fatalf("Expected a summary of 10 suppressed failures, got %v %d", ok, suppressed)
+	}
+	if _, ok := l.report("http://audit", errUnavailable); ok {
+		t.Fatal("Expected the failure to be suppressed again")
+	}
+}
+
+func TestAuditFailureLimiterFlush(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		summaries = make(map[string]int)
+	)
+	l := newAuditFailureLimiter(time.Hour, func(target string, err error, suppressed int) {
+		mu.Lock()
+		summaries[target+": "+err.Error()] += suppressed
+		mu.Unlock()
+	})
+	errUnavailable := errors.New("audit target unavailable")
+
+	l.report("http://audit", errUnavailable)
+	for i := 0; i < 5; i++ {
+		l.report("http://audit", errUnavailable)
+	}
+	l.report("http://audit2", errUnavailable)
+
+	// Nothing is flushed before the interval elapsed.
+	l.flush(time.Now())
+	if len(summaries) != 0 {
+		t.Fatalf("Expected no summaries yet, got %v", summaries)
+	}
+
+	// Suppressed failures are summarized without a further failure,
+	// targets without suppressed failures are forgotten.
+	l.flush(time.Now().Add(time.Hour))
+	if got := summaries["http://audit: audit target unavailable"]; got != 5 || len(summaries) != 1 {
+		t.Fatalf("Expected a summary of 5 suppressed failures, got %v", summaries)
+	}
+	l.mu.Lock()
+	_, found := l.failures["http://audit2: audit target unavailable"]
+	l.mu.Unlock()
+	if found {
+		t.Fatal("Expected the idle target to be forgotten")
+	}
+
+	// The summarized failures are not reported again.
+	l.flush(time.Now().Add(2 * time.Hour))
+	if got := summaries["http://audit: audit target unavailable"]; got != 5 {
+		t.Fatalf("Expected suppressed failures to be summarized once, got %d", got)
+	}
+}
@@ -31,7 +31,6 @@ import (
 
 	xhttp "github.com/minio/minio/internal/http"
 	"github.com/minio/minio/internal/logger"
-	xnet "github.com/minio/pkg/net"
 )
 
 // DefaultTimeout - default REST timeout is 10 seconds.
@@ -43,19 +42,6 @@ const (
 	closed
 )
 
-// Hold the number of failed RPC calls due to networking errors
-var networkErrsCounter uint64
-
-// GetNetworkErrsCounter returns the number of failed RPC requests
-func GetNetworkErrsCounter() uint64 {
-	return atomic.LoadUint64(&networkErrsCounter)
-}
-
-// ResetNetworkErrsCounter resets the number of failed RPC requests
-func ResetNetworkErrsCounter() {
-	atomic.StoreUint64(&networkErrsCounter, 0)
-}
-
 // NetworkError - error type in case of errors related to http/transport
 // for ex. connection refused, connection reset, dns resolution failure etc.
 // All errors returned by storage-rest-server (ex errFileNotFound, errDiskNotFound) are not considered to be network errors.
@@ -135,6 +121,7 @@ func (c *Client) Call(ctx context.Context, method string, values url.Values, bod
 		req.Header.Set("Authorization", "Bearer "+c.newAuthToken(req.URL.RawQuery))
 	}
 	req.Header.Set("X-Minio-Time", time.Now().UTC().Format(time.RFC3339))
+	injectRequestID(ctx, req)
 	if body != nil {
 		req.Header.Set("Expect", "100-continue")
 	}
@@ -143,9 +130,9 @@ func (c *Client) Call(ctx context.Context, method string, values url.Values, bod
 	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		if xnet.IsNetworkOrHostDown(err, c.ExpectTimeouts) {
+		if IsNetworkError(err, c.ExpectTimeouts) {
 			if !c.NoMetrics {
-				atomic.AddUint64(&networkErrsCounter, 1)
+				networkErrs.record(c.url.Host, time.Now())
 			}
 			if c.MarkOffline() {
 				logger.LogIf(ctx, fmt.Errorf("Marking %s temporary offline; caused by %w", c.url.String(), err))
@@ -178,9 +165,9 @@ func (c *Client) Call(ctx context.Context, method string, values url.Values, bod
 		// Limit the ReadAll(), just in case, because of a bug, the server responds with large data.
 		b, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.MaxErrResponseSize))
 		if err != nil {
-			if xnet.IsNetworkOrHostDown(err, c.ExpectTimeouts) {
+			if IsNetworkError(err, c.ExpectTimeouts) {
 				if !c.NoMetrics {
-					atomic.AddUint64(&networkErrsCounter, 1)
+					networkErrs.record(c.url.Host, time.Now())
 				}
 				if c.MarkOffline() {
 					logger.LogIf(ctx, fmt.Errorf("Marking %s temporary offline; caused by %w", c.url.String(), err))
@@ -201,6 +188,22 @@ func (c *Client) Close() {
 	atomic.StoreInt32(&c.connected, closed)
 }
 
+// injectRequestID sets the request ID of the request in ctx on an outgoing
+// internode request, so that logs can be correlated across nodes.
+func injectRequestID(ctx context.Context, req *http.Request) {
+	reqInfo := logger.GetReqInfo(ctx)
+	if reqInfo == nil || reqInfo.RequestID == "" {
+		return
+	}
+	req.Header.Set(xhttp.AmzRequestID, reqInfo.RequestID)
+}
+
+// ExtractRequestID returns the request ID set by the calling node on an
+// incoming internode request, empty if none was set.
+func ExtractRequestID(r *http.Request) string {
+	return r.Header.Get(xhttp.AmzRequestID)
+}
+
 // NewClient - returns new REST client.
 func NewClient(url *url.URL, tr http.RoundTripper, newAuthToken func(aud string) string) *Client {
 	// Transport is exactly same as Go default in https://golang.org/pkg/net/http/#RoundTripper
@@ -18,10 +18,20 @@
 package rest
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"syscall"
 	"testing"
+	"time"
+
+	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger"
 )
 
 func TestNetworkError_Unwrap(t *testing.T) {
@@ -68,3 +78,108 @@ func TestNetworkError_Unwrap(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkErrsWindow(t *testing.T) {
+	w := newNetworkErrsWindow(time.Minute)
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 30; i++ {
+		w.record("node1:9000", now.Add(time.Duration(i)*time.Second))
+	}
+	for i := 0; i < 6; i++ {
+		w.record("node2:9000", now.Add(time.Duration(i)*10*time.Second))
+	}
+
+	now = now.Add(59 * time.Second)
+	rates := w.rates(now)
+	if len(rates) != 2 {
+		t.Fatalf("expected 2 peers, got %v", rates)
+	}
+	if rates["node1:9000"] != 0.5 {
+		t.Errorf("expected node1 rate 0.5, got %v", rates["node1:9000"])
+	}
+	if rates["node2:9000"] != 0.1 {
+		t.Errorf("expected node2 rate 0.1, got %v", rates["node2:9000"])
+	}
+
+	// Errors older than the window are dropped.
+	rates = w.rates(now.Add(40 * time.Second))
+	if _, ok := rates["node1:9000"]; ok {
+		t.Errorf("expected node1 errors to be out of the window, got %v", rates)
+	}
+	if rates["node2:9000"] != 2.0/60 {
+		t.Errorf("expected node2 rate %v, got %v", 2.0/60, rates["node2:9000"])
+	}
+
+	// Total is aggregated across peers.
+	if total := w.loadAndResetTotal(); total != 36 {
+		t.Errorf("expected 36 errors in total, got %d", total)
+	}
+	if total := w.loadTotal(); total != 0 {
+		t.Errorf("expected total to be reset, got %d", total)
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	testCases := []struct {
+		err            error
+		expectTimeouts bool
+		want           bool
+	}{
+		{err: nil, want: false},
+		{err: io.ErrUnexpectedEOF, want: true},
+		{err: syscall.ECONNREFUSED, want: true},
+		{err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{err: &url.Error{Op: "Post", URL: "http://node1", Err: restError("remote server offline")}, want: true},
+		{err: &NetworkError{Err: &url.Error{Op: "Post", URL: "http://node1", Err: io.ErrUnexpectedEOF}}, want: true},
+		// Context cancellation never counts, expired deadlines only
+		// if timeouts are not expected.
+		{err: context.Canceled, want: false},
+		{err: context.Canceled, expectTimeouts: true, want: false},
+		{err: context.DeadlineExceeded, want: true},
+		{err: context.DeadlineExceeded, expectTimeouts: true, want: false},
+		{err: errors.New("file not found"), want: false},
+	}
+	for i, tc := range testCases {
+		if got := IsNetworkError(tc.err, tc.expectTimeouts); got != tc.want {
+			t.Errorf("Test %d: expected %t for %v, got %t", i+1, tc.want, tc.err, got)
+		}
+	}
+}
+
+func TestCallRequestID(t *testing.T) {
+	const requestID = "16FC9DC0B1C37E8E"
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ExtractRequestID(r)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(u, http.DefaultTransport, nil)
+	c.NoMetrics = true
+
+	ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{RequestID: requestID})
+	resp, err := c.Call(ctx, "/serverinfo", nil, nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xhttp.DrainBody(resp)
+	if got != requestID {
+		t.Fatalf("Expected request ID %s, got %s", requestID, got)
+	}
+
+	// Nothing is sent without a request ID.
+	resp, err = c.Call(context.Background(), "/serverinfo", nil, nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xhttp.DrainBody(resp)
+	if got != "" {
+		t.Fatalf("Expected no request ID, got %s", got)
+	}
+}
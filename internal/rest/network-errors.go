@@ -0,0 +1,164 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"syscall"
+	"time"
+
+	xnet "github.com/minio/pkg/net"
+)
+
+// IsNetworkError returns true if err is caused by the network, such as
+// timeouts, refused or reset connections, truncated streams and hosts
+// which are down. Context cancellation is never a network error, an
+// expired context deadline is one unless expectTimeouts is set.
+func IsNetworkError(err error, expectTimeouts bool) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return !expectTimeouts
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return xnet.IsNetworkOrHostDown(err, expectTimeouts)
+}
+
+// networkErrsWindowSlots is the number of slots the rolling window
+// is divided into, each slot counts the errors of one window/slots
+// interval.
+const networkErrsWindowSlots = 60
+
+// networkErrsWindow counts failed RPC calls due to networking errors,
+// per peer over a rolling time window and in total since the last reset.
+type networkErrsWindow struct {
+	mu       sync.Mutex
+	slotSize time.Duration
+	total    uint64
+	peers    map[string]*peerErrsSlots
+}
+
+type peerErrsSlots struct {
+	counts [networkErrsWindowSlots]uint64
+	epochs [networkErrsWindowSlots]int64
+}
+
+func newNetworkErrsWindow(window time.Duration) *networkErrsWindow {
+	return &networkErrsWindow{
+		slotSize: window / networkErrsWindowSlots,
+		peers:    make(map[string]*peerErrsSlots),
+	}
+}
+
+// record counts one network error for peer at now.
+func (w *networkErrsWindow) record(peer string, now time.Time) {
+	epoch := now.UnixNano() / int64(w.slotSize)
+	idx := epoch % networkErrsWindowSlots
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.total++
+	p, ok := w.peers[peer]
+	if !ok {
+		p = &peerErrsSlots{}
+		w.peers[peer] = p
+	}
+	if p.epochs[idx] != epoch {
+		p.epochs[idx] = epoch
+		p.counts[idx] = 0
+	}
+	p.counts[idx]++
+}
+
+// rates returns the number of network errors per second of each peer
+// over the window ending at now. Peers without errors in the window
+// are dropped.
+func (w *networkErrsWindow) rates(now time.Time) map[string]float64 {
+	epoch := now.UnixNano() / int64(w.slotSize)
+	window := w.slotSize * networkErrsWindowSlots
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rates := make(map[string]float64, len(w.peers))
+	for peer, p := range w.peers {
+		var count uint64
+		for i := range p.counts {
+			if epoch-p.epochs[i] < networkErrsWindowSlots {
+				count += p.counts[i]
+			}
+		}
+		if count == 0 {
+			delete(w.peers, peer)
+			continue
+		}
+		rates[peer] = float64(count) / window.Seconds()
+	}
+	return rates
+}
+
+// loadTotal returns the total number of network errors since the last reset.
+func (w *networkErrsWindow) loadTotal() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.total
+}
+
+// loadAndResetTotal returns the total number of network errors since
+// the last reset and resets it, per peer rates are not affected.
+func (w *networkErrsWindow) loadAndResetTotal() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	total := w.total
+	w.total = 0
+	return total
+}
+
+// Hold the number of failed RPC calls due to networking errors
+var networkErrs = newNetworkErrsWindow(time.Minute)
+
+// GetNetworkErrsCounter returns the number of failed RPC requests
+func GetNetworkErrsCounter() uint64 {
+	return networkErrs.loadTotal()
+}
+
+// ResetNetworkErrsCounter resets the number of failed RPC requests
+func ResetNetworkErrsCounter() {
+	networkErrs.loadAndResetTotal()
+}
+
+// LoadAndResetNetworkErrsCounter returns the number of failed RPC requests
+// and resets it atomically.
+func LoadAndResetNetworkErrsCounter() uint64 {
+	return networkErrs.loadAndResetTotal()
+}
+
+// GetNetworkErrsRates returns the number of failed RPC requests per second
+// over the last minute for each peer host with errors.
+func GetNetworkErrsRates() map[string]float64 {
+	return networkErrs.rates(time.Now())
+}